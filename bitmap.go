@@ -0,0 +1,293 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <ft2build.h>
+#include FT_FREETYPE_H
+
+// newCBitmap allocates an FT_Bitmap holding a copy of the given buffer,
+// leaving the conversion of the fields to their types, which differ between
+// FreeType versions, to C.
+static FT_Bitmap* newCBitmap(int width, int rows, int pitch, int mode, int numGrays, const void* buf, size_t size) {
+	FT_Bitmap* b = calloc(1, sizeof(FT_Bitmap));
+	if (b == NULL) {
+		return NULL;
+	}
+	b->width = width;
+	b->rows = rows;
+	b->pitch = pitch;
+	b->pixel_mode = mode;
+	b->num_grays = numGrays;
+	if (size > 0) {
+		b->buffer = malloc(size);
+		if (b->buffer == NULL) {
+			free(b);
+			return NULL;
+		}
+		memcpy(b->buffer, buf, size);
+	}
+	return b;
+}
+
+static void freeCBitmap(FT_Bitmap* b) {
+	free(b->buffer);
+	free(b);
+}
+*/
+import "C"
+
+import (
+	"image"
+	"unsafe"
+)
+
+// pixelMode is the pixel format of a FreeType bitmap, see FT_Pixel_Mode.
+type pixelMode uint8
+
+const (
+	pixelModeMono  pixelMode = C.FT_PIXEL_MODE_MONO
+	pixelModeGray  pixelMode = C.FT_PIXEL_MODE_GRAY
+	pixelModeGray2 pixelMode = C.FT_PIXEL_MODE_GRAY2
+	pixelModeGray4 pixelMode = C.FT_PIXEL_MODE_GRAY4
+	pixelModeLCD   pixelMode = C.FT_PIXEL_MODE_LCD
+	pixelModeLCDV  pixelMode = C.FT_PIXEL_MODE_LCD_V
+	pixelModeBGRA  pixelMode = C.FT_PIXEL_MODE_BGRA
+)
+
+// bitmap is a view of the data of a FreeType FT_Bitmap.
+type bitmap struct {
+	// Width and number of rows of the bitmap, in pixels. For LCD bitmaps the
+	// width (or number of rows for LCD_V) is three times the size of the image.
+	width, rows int
+
+	// Number of bytes per row. If negative the rows are stored bottom-up.
+	pitch int
+
+	// Pixel format and, for pixelModeGray, the number of gray levels.
+	mode     pixelMode
+	numGrays int
+
+	// The bitmap buffer, rows*abs(pitch) bytes.
+	buf []byte
+
+	// Distance from the glyph origin to the left-most column and to the top
+	// row of the bitmap, in pixels. Positive top values extend upward.
+	left, top int
+}
+
+// newBitmap returns a view of the given FreeType bitmap placed at the given
+// left and top offsets, it is only valid for as long as the FreeType bitmap is.
+func newBitmap(b *C.FT_Bitmap, left, top C.FT_Int) *bitmap {
+	bm := &bitmap{
+		left:     int(left),
+		top:      int(top),
+		width:    int(b.width),
+		rows:     int(b.rows),
+		pitch:    int(b.pitch),
+		mode:     pixelMode(b.pixel_mode),
+		numGrays: int(b.num_grays),
+	}
+	if n := bm.absPitch() * bm.rows; n > 0 && b.buffer != nil {
+		bm.buf = unsafe.Slice((*byte)(unsafe.Pointer(b.buffer)), n)
+	}
+	return bm
+}
+
+// cBitmap returns a copy of the bitmap's fields and buffer in a C allocated
+// FT_Bitmap, to be released with freeCBitmap, or nil if it cannot be allocated.
+func (b *bitmap) cBitmap() *C.FT_Bitmap {
+	var buf unsafe.Pointer
+	if len(b.buf) > 0 {
+		buf = unsafe.Pointer(&b.buf[0])
+	}
+	return C.newCBitmap(
+		C.int(b.width),
+		C.int(b.rows),
+		C.int(b.pitch),
+		C.int(b.mode),
+		C.int(b.numGrays),
+		buf,
+		C.size_t(len(b.buf)),
+	)
+}
+
+// freeCBitmap releases a bitmap returned by cBitmap.
+func freeCBitmap(c *C.FT_Bitmap) {
+	C.freeCBitmap(c)
+}
+
+func (b *bitmap) absPitch() int {
+	if b.pitch < 0 {
+		return -b.pitch
+	}
+	return b.pitch
+}
+
+// row returns the bytes of the row y, counting from the top of the bitmap.
+func (b *bitmap) row(y int) []byte {
+	p := b.absPitch()
+	if b.pitch < 0 {
+		y = b.rows - 1 - y
+	}
+	return b.buf[y*p : (y+1)*p]
+}
+
+// rowBytes returns the minimum number of bytes per row needed to hold the
+// bitmap's pixels.
+func (b *bitmap) rowBytes() int {
+	switch b.mode {
+	case pixelModeMono:
+		return (b.width + 7) / 8
+	case pixelModeGray2:
+		return (b.width + 3) / 4
+	case pixelModeGray4:
+		return (b.width + 1) / 2
+	case pixelModeBGRA:
+		return b.width * 4
+	}
+	return b.width
+}
+
+// bounds returns the bounds of the bitmap's image relative to the glyph origin,
+// with positive Y values extending downward.
+func (b *bitmap) bounds() image.Rectangle {
+	w, h := b.width, b.rows
+	switch b.mode {
+	case pixelModeLCD:
+		w /= 3
+	case pixelModeLCDV:
+		h /= 3
+	}
+	return image.Rect(0, 0, w, h).Add(image.Pt(b.left, -b.top))
+}
+
+// image copies the bitmap into a new image of the matching Go type, whose
+// bounds are given by b.bounds():
+//
+//	Mono         -> *image.Paletted (with MonoPalette)
+//	Gray         -> *image.Alpha
+//	Gray2, Gray4 -> *image.Alpha (scaled to 8-bit)
+//	LCD, LCD_V   -> *image.NRGBA (subpixel coverage, opaque alpha)
+//	BGRA         -> *image.RGBA
+//
+// ErrInvalidGlyphFormat is returned for unknown pixel modes, and
+// ErrInvalidArgument if the buffer is too small for the bitmap.
+func (b *bitmap) image() (image.Image, error) {
+	if b.width < 0 || b.rows < 0 || b.absPitch() < b.rowBytes() || len(b.buf) < b.absPitch()*b.rows {
+		return nil, ErrInvalidArgument
+	}
+
+	r := b.bounds()
+	switch b.mode {
+	case pixelModeMono:
+		img := image.NewPaletted(r, MonoPalette)
+		for y := 0; y < b.rows; y++ {
+			src := b.row(y)
+			dst := img.Pix[y*img.Stride:]
+			for x := 0; x < b.width; x++ {
+				dst[x] = (src[x/8] >> (7 - uint(x%8))) & 1
+			}
+		}
+		return img, nil
+
+	case pixelModeGray:
+		img := image.NewAlpha(r)
+		for y := 0; y < b.rows; y++ {
+			dst := img.Pix[y*img.Stride : y*img.Stride+b.width]
+			copy(dst, b.row(y))
+			if b.numGrays > 1 && b.numGrays != 256 {
+				for x, v := range dst {
+					dst[x] = uint8(int(v) * 255 / (b.numGrays - 1))
+				}
+			}
+		}
+		return img, nil
+
+	case pixelModeGray2, pixelModeGray4:
+		bits := uint(2)
+		if b.mode == pixelModeGray4 {
+			bits = 4
+		}
+		perByte := 8 / bits
+		max := 1<<bits - 1
+		img := image.NewAlpha(r)
+		for y := 0; y < b.rows; y++ {
+			src := b.row(y)
+			dst := img.Pix[y*img.Stride:]
+			for x := 0; x < b.width; x++ {
+				shift := 8 - bits*(uint(x)%perByte+1)
+				v := int(src[uint(x)/perByte]>>shift) & max
+				dst[x] = uint8(v * 255 / max)
+			}
+		}
+		return img, nil
+
+	case pixelModeLCD:
+		img := image.NewNRGBA(r)
+		for y := 0; y < b.rows; y++ {
+			src := b.row(y)
+			dst := img.Pix[y*img.Stride:]
+			for x := 0; x < r.Dx(); x++ {
+				copy(dst[x*4:x*4+3], src[x*3:x*3+3])
+				dst[x*4+3] = 0xff
+			}
+		}
+		return img, nil
+
+	case pixelModeLCDV:
+		img := image.NewNRGBA(r)
+		for y := 0; y < r.Dy(); y++ {
+			r, g, bl := b.row(y*3), b.row(y*3+1), b.row(y*3+2)
+			dst := img.Pix[y*img.Stride:]
+			for x := 0; x < b.width; x++ {
+				dst[x*4+0] = r[x]
+				dst[x*4+1] = g[x]
+				dst[x*4+2] = bl[x]
+				dst[x*4+3] = 0xff
+			}
+		}
+		return img, nil
+
+	case pixelModeBGRA:
+		// FreeType's BGRA bitmaps are premultiplied, just like image.RGBA.
+		img := image.NewRGBA(r)
+		for y := 0; y < b.rows; y++ {
+			src := b.row(y)
+			dst := img.Pix[y*img.Stride:]
+			for x := 0; x < b.width; x++ {
+				dst[x*4+0] = src[x*4+2]
+				dst[x*4+1] = src[x*4+1]
+				dst[x*4+2] = src[x*4+0]
+				dst[x*4+3] = src[x*4+3]
+			}
+		}
+		return img, nil
+	}
+	return nil, ErrInvalidGlyphFormat
+}
+
+// drawOver composites the 8-bit gray bitmap over dst, placing the glyph
+// origin at the given point.
+func (b *bitmap) drawOver(dst *image.Alpha, at image.Point) error {
+	if b.width < 0 || b.rows < 0 || b.absPitch() < b.width || len(b.buf) < b.absPitch()*b.rows {
+		return ErrInvalidArgument
+	}
+	min := b.bounds().Min.Add(at)
+	r := b.bounds().Add(at).Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		src := b.row(y - min.Y)
+		i := dst.PixOffset(r.Min.X, y)
+		for x := r.Min.X; x < r.Max.X; x++ {
+			s := uint32(src[x-min.X])
+			d := uint32(dst.Pix[i])
+			dst.Pix[i] = uint8(s + d*(255-s)/255)
+			i++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestBitmapImage(t *testing.T) {
+	tests := []struct {
+		name   string
+		bitmap bitmap
+		want   image.Image
+	}{
+		{
+			name: "gray padded",
+			bitmap: bitmap{
+				width: 2, rows: 2, pitch: 4, mode: pixelModeGray, numGrays: 256,
+				buf: []byte{
+					0x10, 0x20, 0xEE, 0xEE,
+					0x30, 0x40, 0xEE, 0xEE,
+				},
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0x10, 0x20, 0x30, 0x40},
+				Stride: 2,
+				Rect:   image.Rect(0, 0, 2, 2),
+			},
+		},
+		{
+			name: "gray placed",
+			bitmap: bitmap{
+				width: 2, rows: 1, pitch: 2, mode: pixelModeGray, numGrays: 256,
+				buf:  []byte{0x10, 0x20},
+				left: 3, top: 7,
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0x10, 0x20},
+				Stride: 2,
+				Rect:   image.Rect(3, -7, 5, -6),
+			},
+		},
+		{
+			name: "gray bottom-up",
+			bitmap: bitmap{
+				width: 2, rows: 2, pitch: -2, mode: pixelModeGray, numGrays: 256,
+				buf: []byte{
+					0x30, 0x40,
+					0x10, 0x20,
+				},
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0x10, 0x20, 0x30, 0x40},
+				Stride: 2,
+				Rect:   image.Rect(0, 0, 2, 2),
+			},
+		},
+		{
+			name: "gray 16 levels",
+			bitmap: bitmap{
+				width: 2, rows: 1, pitch: 2, mode: pixelModeGray, numGrays: 16,
+				buf: []byte{0x0F, 0x05},
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0xFF, 0x55},
+				Stride: 2,
+				Rect:   image.Rect(0, 0, 2, 1),
+			},
+		},
+		{
+			name: "mono",
+			bitmap: bitmap{
+				width: 10, rows: 2, pitch: 4, mode: pixelModeMono,
+				buf: []byte{
+					0xA5, 0x80, 0xFF, 0xFF,
+					0x01, 0x40, 0x00, 0x00,
+				},
+			},
+			want: &image.Paletted{
+				Pix: []byte{
+					1, 0, 1, 0, 0, 1, 0, 1, 1, 0,
+					0, 0, 0, 0, 0, 0, 0, 1, 0, 1,
+				},
+				Stride:  10,
+				Rect:    image.Rect(0, 0, 10, 2),
+				Palette: MonoPalette,
+			},
+		},
+		{
+			name: "gray2",
+			bitmap: bitmap{
+				width: 5, rows: 1, pitch: 2, mode: pixelModeGray2,
+				buf: []byte{0x1B, 0xC0},
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0x00, 0x55, 0xAA, 0xFF, 0xFF},
+				Stride: 5,
+				Rect:   image.Rect(0, 0, 5, 1),
+			},
+		},
+		{
+			name: "gray4",
+			bitmap: bitmap{
+				width: 3, rows: 1, pitch: 2, mode: pixelModeGray4,
+				buf: []byte{0x0F, 0x80},
+			},
+			want: &image.Alpha{
+				Pix:    []byte{0x00, 0xFF, 0x88},
+				Stride: 3,
+				Rect:   image.Rect(0, 0, 3, 1),
+			},
+		},
+		{
+			name: "lcd",
+			bitmap: bitmap{
+				width: 6, rows: 1, pitch: 8, mode: pixelModeLCD,
+				buf: []byte{1, 2, 3, 4, 5, 6, 0xEE, 0xEE},
+			},
+			want: &image.NRGBA{
+				Pix:    []byte{1, 2, 3, 0xFF, 4, 5, 6, 0xFF},
+				Stride: 8,
+				Rect:   image.Rect(0, 0, 2, 1),
+			},
+		},
+		{
+			name: "lcd_v",
+			bitmap: bitmap{
+				width: 1, rows: 3, pitch: 4, mode: pixelModeLCDV,
+				buf: []byte{
+					1, 0xEE, 0xEE, 0xEE,
+					2, 0xEE, 0xEE, 0xEE,
+					3, 0xEE, 0xEE, 0xEE,
+				},
+			},
+			want: &image.NRGBA{
+				Pix:    []byte{1, 2, 3, 0xFF},
+				Stride: 4,
+				Rect:   image.Rect(0, 0, 1, 1),
+			},
+		},
+		{
+			name: "bgra",
+			bitmap: bitmap{
+				width: 1, rows: 2, pitch: 4, mode: pixelModeBGRA,
+				buf: []byte{
+					0x10, 0x20, 0x30, 0x40,
+					0x50, 0x60, 0x70, 0x80,
+				},
+			},
+			want: &image.RGBA{
+				Pix:    []byte{0x30, 0x20, 0x10, 0x40, 0x70, 0x60, 0x50, 0x80},
+				Stride: 4,
+				Rect:   image.Rect(0, 0, 1, 2),
+			},
+		},
+		{
+			name:   "empty",
+			bitmap: bitmap{mode: pixelModeGray, numGrays: 256},
+			want:   &image.Alpha{Pix: []byte{}, Rect: image.Rect(0, 0, 0, 0)},
+		},
+	}
+	for _, tst := range tests {
+		// Read the bitmap through a FreeType FT_Bitmap, as glyphs are.
+		bm, free := viaFreeType(t, &tst.bitmap)
+		if !sameBitmap(bm, &tst.bitmap) {
+			t.Errorf("%s: newBitmap made %+v, want %+v", tst.name, *bm, tst.bitmap)
+		}
+		got, err := bm.image()
+		free()
+		if err != nil {
+			t.Errorf("%s: %v", tst.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tst.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tst.name, got, tst.want)
+		}
+	}
+}
+
+// viaFreeType copies the bitmap into a FreeType FT_Bitmap and returns the view
+// of it made by newBitmap, as for glyphs, and a function releasing it.
+func viaFreeType(t *testing.T, b *bitmap) (view *bitmap, free func()) {
+	c := b.cBitmap()
+	if c == nil {
+		t.Fatal("cannot allocate FT_Bitmap")
+	}
+	view = newBitmap(c, 0, 0)
+	view.left, view.top = b.left, b.top
+	return view, func() { freeCBitmap(c) }
+}
+
+// sameBitmap tells if the bitmaps have the same fields and buffer contents.
+func sameBitmap(a, b *bitmap) bool {
+	return a.width == b.width && a.rows == b.rows && a.pitch == b.pitch &&
+		a.mode == b.mode && a.numGrays == b.numGrays &&
+		a.left == b.left && a.top == b.top && bytes.Equal(a.buf, b.buf)
+}
+
+func TestBitmapImageInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		bitmap bitmap
+		want   error
+	}{
+		{
+			name:   "short buffer",
+			bitmap: bitmap{width: 2, rows: 2, pitch: 2, mode: pixelModeGray, buf: []byte{1, 2, 3}},
+			want:   ErrInvalidArgument,
+		},
+		{
+			name:   "short pitch",
+			bitmap: bitmap{width: 4, rows: 1, pitch: 3, mode: pixelModeGray, buf: []byte{1, 2, 3}},
+			want:   ErrInvalidArgument,
+		},
+		{
+			name:   "unknown mode",
+			bitmap: bitmap{width: 1, rows: 1, pitch: 1, mode: 42, buf: []byte{1}},
+			want:   ErrInvalidGlyphFormat,
+		},
+	}
+	for _, tst := range tests {
+		if _, err := tst.bitmap.image(); err != tst.want {
+			t.Errorf("%s: got error %v, want %v", tst.name, err, tst.want)
+		}
+	}
+
+	// A short pitch is also caught for bitmaps read from FreeType.
+	bm, free := viaFreeType(t, &tests[1].bitmap)
+	defer free()
+	if _, err := bm.image(); err != ErrInvalidArgument {
+		t.Errorf("FreeType bitmap with short pitch: got error %v, want %v", err, ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_TRUETYPE_TABLES_H
+*/
+import "C"
+
+import "unsafe"
+
+// Encoding is a character encoding of a charmap, they map onto FreeType's
+// FT_ENCODING_* values.
+type Encoding uint32
+
+const (
+	// EncodingNone is the encoding of charmaps whose encoding is unknown or which
+	// FreeType cannot interpret.
+	EncodingNone Encoding = C.FT_ENCODING_NONE
+
+	// EncodingMSSymbol is Microsoft's symbol encoding, used by symbol fonts whose
+	// characters are mapped into the U+F020-U+F0FF private use range.
+	EncodingMSSymbol Encoding = C.FT_ENCODING_MS_SYMBOL
+
+	// EncodingUnicode is the Unicode encoding, character codes are code points.
+	EncodingUnicode Encoding = C.FT_ENCODING_UNICODE
+
+	// EncodingSJIS is the Shift JIS encoding of Japanese.
+	EncodingSJIS Encoding = C.FT_ENCODING_SJIS
+
+	// EncodingGB2312 is the GB 2312 encoding of simplified Chinese.
+	EncodingGB2312 Encoding = C.FT_ENCODING_GB2312
+
+	// EncodingBig5 is the Big5 encoding of traditional Chinese.
+	EncodingBig5 Encoding = C.FT_ENCODING_BIG5
+
+	// EncodingWansung is the Wansung (EUC-KR) encoding of Korean.
+	EncodingWansung Encoding = C.FT_ENCODING_WANSUNG
+
+	// EncodingJohab is the Johab encoding of Korean.
+	EncodingJohab Encoding = C.FT_ENCODING_JOHAB
+
+	// EncodingAdobeStandard is Adobe's standard encoding of Type 1 and CFF fonts.
+	EncodingAdobeStandard Encoding = C.FT_ENCODING_ADOBE_STANDARD
+
+	// EncodingAdobeExpert is Adobe's expert encoding of Type 1 and CFF fonts.
+	EncodingAdobeExpert Encoding = C.FT_ENCODING_ADOBE_EXPERT
+
+	// EncodingAdobeCustom is the custom encoding of a Type 1 or CFF font.
+	EncodingAdobeCustom Encoding = C.FT_ENCODING_ADOBE_CUSTOM
+
+	// EncodingAdobeLatin1 is Adobe's Latin-1 encoding of Type 1 fonts.
+	EncodingAdobeLatin1 Encoding = C.FT_ENCODING_ADOBE_LATIN_1
+
+	// EncodingOldLatin2 is a legacy Latin-2 encoding, no longer used.
+	EncodingOldLatin2 Encoding = C.FT_ENCODING_OLD_LATIN_2
+
+	// EncodingAppleRoman is the Mac OS Roman encoding, see CharIterator.Rune.
+	EncodingAppleRoman Encoding = C.FT_ENCODING_APPLE_ROMAN
+)
+
+// String returns the four character tag of the encoding, e.g. "unic".
+func (e Encoding) String() string {
+	if e == EncodingNone {
+		return "none"
+	}
+	return string([]byte{byte(e >> 24), byte(e >> 16), byte(e >> 8), byte(e)})
+}
+
+// Charmap describes a single charmap of a font, which maps character codes to
+// glyph indices.
+type Charmap struct {
+	// The index of the charmap in the font.
+	Index int
+
+	// The encoding of the charmap.
+	Encoding Encoding
+
+	// The platform and encoding IDs of the charmap, as found in the font file
+	// (e.g. 3 and 1 for the Windows Unicode BMP charmap of an SFNT font).
+	PlatformID, EncodingID int
+
+	// The format of the SFNT cmap subtable, or -1 for non-SFNT charmaps.
+	Format int
+
+	// The language ID of the SFNT cmap subtable, this is only meaningful for
+	// Macintosh charmaps and zero otherwise.
+	Language int
+}
+
+// Charmaps returns the list of charmaps the font contains.
+func (f *Font) Charmaps() []Charmap {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil
+	}
+
+	n := int(f.c.num_charmaps)
+	if n == 0 {
+		return nil
+	}
+	cms := make([]Charmap, n)
+	for i, cm := range unsafe.Slice(f.c.charmaps, n) {
+		cms[i] = newCharmap(cm)
+	}
+	return cms
+}
+
+// Charmap returns the active charmap of the font, or false if the font has no
+// active charmap.
+func (f *Font) Charmap() (Charmap, bool) {
+	f.lock()
+	defer f.unlock()
+
+	if f.checkOpen() != nil {
+		return Charmap{}, false
+	}
+
+	if f.c.charmap == nil {
+		return Charmap{}, false
+	}
+	return newCharmap(f.c.charmap), true
+}
+
+// SelectCharmap selects the first charmap of the font with the given encoding
+// as the active one, used by Index and other methods that map runes to glyphs.
+//
+// When an EncodingMSSymbol charmap is active, runes up to U+00FF are also
+// looked up in the U+F0xx range used by symbol fonts. When an
+// EncodingAppleRoman charmap is active, runes are converted to Mac OS Roman
+// before being looked up.
+func (f *Font) SelectCharmap(enc Encoding) error {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return err
+	}
+
+	err := C.FT_Select_Charmap(f.c, C.FT_Encoding(enc))
+	if err != 0 {
+		return f.newError("FT_Select_Charmap", int(err))
+	}
+	return nil
+}
+
+// SetCharmap selects the given charmap, as returned by Charmaps, as the active
+// one. See SelectCharmap for details.
+func (f *Font) SetCharmap(cm Charmap) error {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return err
+	}
+
+	if cm.Index < 0 || cm.Index >= int(f.c.num_charmaps) {
+		return ErrInvalidCharMapHandle
+	}
+	charmaps := unsafe.Slice(f.c.charmaps, int(f.c.num_charmaps))
+	err := C.FT_Set_Charmap(f.c, charmaps[cm.Index])
+	if err != 0 {
+		return f.newError("FT_Set_Charmap", int(err))
+	}
+	return nil
+}
+
+// selectDefaultCharmap selects the charmap used after loading the font: a
+// Unicode charmap if there is one, or else a symbol charmap, or else the first
+// charmap of the font. The font lock must be held.
+func (f *Font) selectDefaultCharmap() {
+	if C.FT_Select_Charmap(f.c, C.FT_ENCODING_UNICODE) == 0 {
+		return
+	}
+	if C.FT_Select_Charmap(f.c, C.FT_ENCODING_MS_SYMBOL) == 0 {
+		return
+	}
+	if f.c.num_charmaps > 0 {
+		C.FT_Set_Charmap(f.c, *f.c.charmaps)
+	}
+}
+
+// charIndex returns the glyph index of the rune in the active charmap, or zero
+// if it is not mapped. The font lock must be held.
+func (f *Font) charIndex(r rune) C.FT_UInt {
+	if f.c.charmap == nil {
+		return 0
+	}
+	switch f.c.charmap.encoding {
+	case C.FT_ENCODING_MS_SYMBOL:
+		index := C.FT_Get_Char_Index(f.c, C.FT_ULong(r))
+		if index == 0 && r >= 0 && r <= 0xFF {
+			index = C.FT_Get_Char_Index(f.c, C.FT_ULong(0xF000|r))
+		}
+		return index
+
+	case C.FT_ENCODING_APPLE_ROMAN:
+		c, ok := toMacRoman(r)
+		if !ok {
+			return 0
+		}
+		return C.FT_Get_Char_Index(f.c, C.FT_ULong(c))
+	}
+	return C.FT_Get_Char_Index(f.c, C.FT_ULong(r))
+}
+
+// newCharmap returns a copy of the given FreeType charmap.
+func newCharmap(cm C.FT_CharMap) Charmap {
+	return Charmap{
+		Index:      int(C.FT_Get_Charmap_Index(cm)),
+		Encoding:   Encoding(cm.encoding),
+		PlatformID: int(cm.platform_id),
+		EncodingID: int(cm.encoding_id),
+		Format:     int(C.FT_Get_CMap_Format(cm)),
+		Language:   int(C.FT_Get_CMap_Language_ID(cm)),
+	}
+}
+
+// macRoman maps the upper half of Mac OS Roman to Unicode, the lower half is
+// identical to ASCII.
+var macRoman = [128]rune{
+	0x00C4, 0x00C5, 0x00C7, 0x00C9, 0x00D1, 0x00D6, 0x00DC, 0x00E1,
+	0x00E0, 0x00E2, 0x00E4, 0x00E3, 0x00E5, 0x00E7, 0x00E9, 0x00E8,
+	0x00EA, 0x00EB, 0x00ED, 0x00EC, 0x00EE, 0x00EF, 0x00F1, 0x00F3,
+	0x00F2, 0x00F4, 0x00F6, 0x00F5, 0x00FA, 0x00F9, 0x00FB, 0x00FC,
+	0x2020, 0x00B0, 0x00A2, 0x00A3, 0x00A7, 0x2022, 0x00B6, 0x00DF,
+	0x00AE, 0x00A9, 0x2122, 0x00B4, 0x00A8, 0x2260, 0x00C6, 0x00D8,
+	0x221E, 0x00B1, 0x2264, 0x2265, 0x00A5, 0x00B5, 0x2202, 0x2211,
+	0x220F, 0x03C0, 0x222B, 0x00AA, 0x00BA, 0x03A9, 0x00E6, 0x00F8,
+	0x00BF, 0x00A1, 0x00AC, 0x221A, 0x0192, 0x2248, 0x2206, 0x00AB,
+	0x00BB, 0x2026, 0x00A0, 0x00C0, 0x00C3, 0x00D5, 0x0152, 0x0153,
+	0x2013, 0x2014, 0x201C, 0x201D, 0x2018, 0x2019, 0x00F7, 0x25CA,
+	0x00FF, 0x0178, 0x2044, 0x20AC, 0x2039, 0x203A, 0xFB01, 0xFB02,
+	0x2021, 0x00B7, 0x201A, 0x201E, 0x2030, 0x00C2, 0x00CA, 0x00C1,
+	0x00CB, 0x00C8, 0x00CD, 0x00CE, 0x00CF, 0x00CC, 0x00D3, 0x00D4,
+	0xF8FF, 0x00D2, 0x00DA, 0x00DB, 0x00D9, 0x0131, 0x02C6, 0x02DC,
+	0x00AF, 0x02D8, 0x02D9, 0x02DA, 0x00B8, 0x02DD, 0x02DB, 0x02C7,
+}
+
+// toMacRoman converts the rune to Mac OS Roman.
+func toMacRoman(r rune) (byte, bool) {
+	if r >= 0 && r < 0x80 {
+		return byte(r), true
+	}
+	for i, m := range macRoman {
+		if m == r {
+			return byte(0x80 + i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,125 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+)
+
+// sfntTable returns the named table of the given SFNT font file, as a slice of
+// the file data.
+func sfntTable(t *testing.T, data []byte, tag string) []byte {
+	be := binary.BigEndian
+	numTables := int(be.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := data[12+16*i:]
+		if string(rec[:4]) == tag {
+			off, length := be.Uint32(rec[8:]), be.Uint32(rec[12:])
+			return data[off : off+length]
+		}
+	}
+	t.Fatalf("font has no %q table", tag)
+	return nil
+}
+
+func TestCharmaps(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	cms := font.Charmaps()
+	if len(cms) != 2 {
+		t.Fatalf("got %d charmaps, want 2", len(cms))
+	}
+	var mac Charmap
+	for i, cm := range cms {
+		if cm.Index != i {
+			t.Errorf("charmap %d: Index %d", i, cm.Index)
+		}
+		switch cm.Encoding {
+		case EncodingUnicode:
+			if cm.PlatformID != 3 || cm.EncodingID != 1 || cm.Format != 4 {
+				t.Errorf("unicode charmap: %+v", cm)
+			}
+		case EncodingAppleRoman:
+			if cm.PlatformID != 1 || cm.EncodingID != 0 || cm.Format != 0 {
+				t.Errorf("apple roman charmap: %+v", cm)
+			}
+			mac = cm
+		default:
+			t.Errorf("unexpected charmap: %+v", cm)
+		}
+	}
+
+	if cm, ok := font.Charmap(); !ok || cm.Encoding != EncodingUnicode {
+		t.Fatalf("active charmap is %v, want %v", cm.Encoding, EncodingUnicode)
+	}
+	want := font.Index('é')
+	if want == 0 {
+		t.Fatal("no glyph for 'é'")
+	}
+
+	// Runes are converted to Mac OS Roman by Apple Roman charmaps.
+	if err := font.SetCharmap(mac); err != nil {
+		t.Fatal(err)
+	}
+	if got := font.Index('é'); got != want {
+		t.Errorf("Apple Roman: Index('é') = %d, want %d", got, want)
+	}
+
+	if err := font.SelectCharmap(EncodingBig5); err == nil {
+		t.Error("expected error selecting a missing charmap")
+	}
+	if err := font.SelectCharmap(EncodingUnicode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSymbolCharmap(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Turn the Windows Unicode charmap into a symbol one, and move all of its
+	// mappings for ASCII characters into the U+F0xx range.
+	be := binary.BigEndian
+	cmap := sfntTable(t, data, "cmap")
+	for i := 0; i < int(be.Uint16(cmap[2:])); i++ {
+		rec := cmap[4+8*i:]
+		if be.Uint16(rec) != 3 || be.Uint16(rec[2:]) != 1 {
+			continue
+		}
+		be.PutUint16(rec[2:], 0)
+
+		sub := cmap[be.Uint32(rec[4:]):]
+		segs := int(be.Uint16(sub[6:])) / 2
+		ends, starts, deltas := sub[14:], sub[16+2*segs:], sub[16+4*segs:]
+		for s := 0; s < segs; s++ {
+			end, start := be.Uint16(ends[2*s:]), be.Uint16(starts[2*s:])
+			if end < 0x80 {
+				be.PutUint16(ends[2*s:], end|0xF000)
+				be.PutUint16(starts[2*s:], start|0xF000)
+				be.PutUint16(deltas[2*s:], be.Uint16(deltas[2*s:])-0xF000)
+			}
+		}
+	}
+
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm, ok := font.Charmap(); !ok || cm.Encoding != EncodingMSSymbol {
+		t.Fatalf("active charmap is %v, want %v", cm.Encoding, EncodingMSSymbol)
+	}
+	a, f0a := font.Index('A'), font.Index(0xF041)
+	if a == 0 || a != f0a {
+		t.Errorf("Index('A') = %d, Index(U+F041) = %d", a, f0a)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"image"
+	"io/ioutil"
+	"runtime"
+	"testing"
+)
+
+func TestFontClose(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	index := font.Index('A')
+	g, err := font.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistent, err := g.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := font.NewSizePixels(0, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := font.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	if _, err := font.Load(index); err != ErrClosed {
+		t.Errorf("Load: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := font.SetSizePixels(0, 12); err != ErrClosed {
+		t.Errorf("SetSizePixels: got error %v, want %v", err, ErrClosed)
+	}
+	if _, _, err := font.MeasureString("A"); err != ErrClosed {
+		t.Errorf("MeasureString: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := font.Table(MakeTag("head")); err != ErrClosed {
+		t.Errorf("Table: got error %v, want %v", err, ErrClosed)
+	}
+	if got := font.Index('A'); got != 0 {
+		t.Errorf("Index('A') = %d, want 0", got)
+	}
+	if font.Chars().Next() {
+		t.Error("Chars().Next() returned true")
+	}
+	if c := font.Coverage(); len(c.Blocks) != 0 {
+		t.Errorf("Coverage() has %d blocks", len(c.Blocks))
+	}
+	if _, err := g.Image(); err != ErrClosed {
+		t.Errorf("Glyph.Image: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := persistent.Render(RenderNormal); err != ErrClosed {
+		t.Errorf("Glyph.Render: got error %v, want %v", err, ErrClosed)
+	}
+	if err := g.RenderInto(image.NewAlpha(image.Rect(0, 0, 8, 8)), image.Point{}); err != ErrClosed {
+		t.Errorf("Glyph.RenderInto: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := g.Outline(); err != ErrClosed {
+		t.Errorf("Glyph.Outline: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := size.Load(index); err != ErrClosed {
+		t.Errorf("Size.Load: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := font.NewSizePixels(0, 12); err != ErrClosed {
+		t.Errorf("NewSizePixels: got error %v, want %v", err, ErrClosed)
+	}
+
+	// Finalizers of the closed font's sizes and glyphs must not crash.
+	g, persistent, size = nil, nil, nil
+	runtime.GC()
+	runtime.GC()
+}
+
+func TestContextClose(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := font.Load(font.Index('A'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistent, err := g.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := font.NewSizePixels(0, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the context releases the fonts which are still open.
+	if err := ctx.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if err := font.Close(); err != nil {
+		t.Fatalf("Font.Close after Context.Close: %v", err)
+	}
+
+	if _, err := ctx.Load(data); err != ErrClosed {
+		t.Errorf("Context.Load: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := ctx.NumFaces(data); err != ErrClosed {
+		t.Errorf("Context.NumFaces: got error %v, want %v", err, ErrClosed)
+	}
+	if err := ctx.SetLcdFilter(LcdFilterDefault); err != ErrClosed {
+		t.Errorf("Context.SetLcdFilter: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := font.Load(0); err != ErrClosed {
+		t.Errorf("Font.Load: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := persistent.Copy(); err != ErrClosed {
+		t.Errorf("Glyph.Copy: got error %v, want %v", err, ErrClosed)
+	}
+	if _, err := size.SetSizePixels(0, 24); err != ErrClosed {
+		t.Errorf("Size.SetSizePixels: got error %v, want %v", err, ErrClosed)
+	}
+
+	// Finalizers of objects of the closed context must not crash.
+	font, closed, g, persistent, size = nil, nil, nil, nil, nil
+	runtime.GC()
+	runtime.GC()
+}
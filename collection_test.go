@@ -0,0 +1,137 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// makeCollection builds a TrueType collection (TTC) file from the given font
+// files.
+func makeCollection(t *testing.T, names ...string) []byte {
+	be := binary.BigEndian
+	header := 12 + 4*len(names)
+	ttc := make([]byte, header)
+	copy(ttc, "ttcf")
+	be.PutUint32(ttc[4:], 0x00010000)
+	be.PutUint32(ttc[8:], uint32(len(names)))
+	for i, name := range names {
+		data, err := ioutil.ReadFile("vera/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for len(ttc)%4 != 0 {
+			ttc = append(ttc, 0)
+		}
+		base := len(ttc)
+		be.PutUint32(ttc[12+4*i:], uint32(base))
+
+		// Table offsets are relative to the start of the collection.
+		numTables := int(be.Uint16(data[4:]))
+		for j := 0; j < numTables; j++ {
+			rec := data[12+16*j:]
+			be.PutUint32(rec[8:], be.Uint32(rec[8:])+uint32(base))
+		}
+		ttc = append(ttc, data...)
+	}
+	return ttc
+}
+
+func TestCollection(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttc := makeCollection(t, "Vera.ttf", "VeraBd.ttf", "VeraMono.ttf")
+
+	n, err := ctx.NumFaces(ttc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("NumFaces = %d, want 3", n)
+	}
+
+	want := []string{"Roman", "Bold", "Roman"}
+	var fonts []*Font
+	for i := 0; i < n; i++ {
+		font, err := ctx.LoadFace(ttc, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if font.FaceIndex != i || font.NumFaces != n {
+			t.Errorf("face %d: FaceIndex %d NumFaces %d", i, font.FaceIndex, font.NumFaces)
+		}
+		if font.StyleName != want[i] {
+			t.Errorf("face %d: StyleName %q, want %q", i, font.StyleName, want[i])
+		}
+		if _, err := font.Load(font.Index('A')); err != nil {
+			t.Errorf("face %d: %v", i, err)
+		}
+		fonts = append(fonts, font)
+	}
+	if !fonts[2].IsFixedWidth() {
+		t.Error("face 2 is not fixed width")
+	}
+
+	// All faces share the same font data.
+	for _, f := range fonts[1:] {
+		if &f.data[0] != &fonts[0].data[0] {
+			t.Error("faces do not share font data")
+		}
+	}
+
+	if _, err := ctx.LoadFace(ttc, n); err == nil {
+		t.Error("expected error loading out of range face")
+	}
+	if n, err := ctx.NumFaces(nil); err == nil {
+		t.Errorf("NumFaces(nil) = %d, expected error", n)
+	}
+}
+
+func TestCollectionStream(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	ttc := makeCollection(t, "Vera.ttf", "VeraBd.ttf", "VeraMono.ttf")
+	path := filepath.Join(t.TempDir(), "vera.ttc")
+	if err := ioutil.WriteFile(path, ttc, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, font *Font, err error, index int, style string) {
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if font.FaceIndex != index || font.StyleName != style {
+			t.Errorf("%s: face %d %q, want face %d %q", name, font.FaceIndex, font.StyleName, index, style)
+		}
+		if _, err := font.Load(font.Index('A')); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	font, err := ctx.LoadFileFace(path, 1)
+	check("LoadFileFace", font, err, 1, "Bold")
+	clone, err := font.Clone(ctx)
+	check("clone of LoadFileFace", clone, err, 1, "Bold")
+	font, err = ctx.LoadReaderAtFace(bytes.NewReader(ttc), int64(len(ttc)), 2)
+	check("LoadReaderAtFace", font, err, 2, "Roman")
+	if !font.IsFixedWidth() {
+		t.Error("LoadReaderAtFace: face 2 is not fixed width")
+	}
+
+	if _, err := ctx.LoadFileFace(path, 3); err == nil {
+		t.Error("LoadFileFace: expected error loading out of range face")
+	}
+	if _, err := ctx.LoadReaderAtFace(bytes.NewReader(ttc), int64(len(ttc)), -1); err != ErrInvalidArgument {
+		t.Errorf("LoadReaderAtFace: got error %v for negative face index, want %v", err, ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// renderRunes renders each rune of the text using the font, returning the
+// pixels of each glyph image.
+func renderRunes(font *Font, text string) ([][]byte, error) {
+	var pix [][]byte
+	for _, r := range text {
+		g, err := font.Load(font.Index(r))
+		if err != nil {
+			return nil, err
+		}
+		img, err := g.Image()
+		if err != nil {
+			return nil, err
+		}
+		pix = append(pix, img.Pix)
+	}
+	return pix, nil
+}
+
+const parallelText = "The quick brown fox jumps over the lazy dog 0123456789"
+
+func TestClone(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 17); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.SelectCharmap(EncodingAppleRoman); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	clone, err := font.Clone(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &clone.data[0] != &font.data[0] {
+		t.Error("clone does not share the font file data")
+	}
+	if clone.FamilyName != font.FamilyName || clone.NumGlyphs != font.NumGlyphs {
+		t.Errorf("clone is %q with %d glyphs, want %q with %d", clone.FamilyName, clone.NumGlyphs, font.FamilyName, font.NumGlyphs)
+	}
+	if got, want := clone.SizeMetrics(), font.SizeMetrics(); got != want {
+		t.Errorf("clone size metrics %+v, want %+v", got, want)
+	}
+	if cm, _ := clone.Charmap(); cm.Encoding != EncodingAppleRoman {
+		t.Errorf("clone charmap %v, want %v", cm.Encoding, EncodingAppleRoman)
+	}
+
+	want, err := renderRunes(font, parallelText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := renderRunes(clone, parallelText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("glyph %d of clone differs from that of the font", i)
+		}
+	}
+
+	// Closing the font leaves the clone intact.
+	font.Close()
+	if _, err := renderRunes(clone, "A"); err != nil {
+		t.Error(err)
+	}
+	if _, err := font.Clone(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Clone of closed font: got error %v, want ErrClosed", err)
+	}
+}
+
+func TestCloneError(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.LoadMappedFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A clone which cannot be given the size of the font is closed, releasing
+	// its reference to the mapping.
+	errResize := errors.New("resize failed")
+	font.resize = func(f *Font) (SizeMetrics, error) {
+		return SizeMetrics{}, errResize
+	}
+	if _, err := font.Clone(ctx); err != errResize {
+		t.Fatalf("Clone: got error %v, want %v", err, errResize)
+	}
+	if refs := font.mapping.refs; refs != 1 {
+		t.Errorf("mapping has %d references after failed Clone, want 1", refs)
+	}
+}
+
+func TestParallelClones(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	want, err := renderRunes(font, parallelText)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		clone, err := font.Clone(font.ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 10; n++ {
+				got, err := renderRunes(clone, parallelText)
+				if err != nil {
+					errs <- err
+					return
+				}
+				for j := range want {
+					if !bytes.Equal(got[j], want[j]) {
+						errs <- errors.New("glyph image differs")
+						return
+					}
+				}
+			}
+			errs <- nil
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestParallelSharedFont(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	size, err := font.NewSizePixels(0, 31)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Goroutines using a single font, its sizes and persistent glyphs are
+	// serialised, but must not interfere with each other.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, r := range parallelText {
+				var (
+					g   *Glyph
+					err error
+				)
+				if i%2 == 0 {
+					g, err = font.Load(font.Index(r))
+				} else {
+					g, err = size.Load(font.Index(r))
+				}
+				if err == nil {
+					g, err = g.Copy()
+				}
+				if err == nil {
+					_, err = g.Render(RenderNormal)
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			if _, _, err := font.MeasureString(parallelText); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Release the persistent glyphs while the context is in use.
+	runtime.GC()
+	if _, err := renderRunes(font, "A"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextPool(t *testing.T) {
+	var pool ContextPool
+	a, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Fatal("Get returned a context which is in use")
+	}
+	pool.Put(a)
+	if c, _ := pool.Get(); c != a {
+		t.Error("Get did not reuse the free context")
+	}
+
+	font := loadTestFont(t, "Vera.ttf")
+	clone, err := font.Clone(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clone.Load(0); !errors.Is(err, ErrClosed) {
+		t.Errorf("Load from closed pool context: got error %v, want ErrClosed", err)
+	}
+	if _, err := pool.Get(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get from closed pool: got error %v, want ErrClosed", err)
+	}
+	pool.Put(b)
+}
+
+func benchmarkRender(b *testing.B, font func() (*Font, error)) {
+	b.RunParallel(func(pb *testing.PB) {
+		f, err := font()
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		runes := []rune(parallelText)
+		for i := 0; pb.Next(); i++ {
+			g, err := f.Load(f.Index(runes[i%len(runes)]))
+			if err == nil {
+				_, err = g.Image()
+			}
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkRenderShared renders from a single font in all goroutines, which is
+// serialised.
+func BenchmarkRenderShared(b *testing.B) {
+	font := loadTestFont(b, "Vera.ttf")
+	benchmarkRender(b, func() (*Font, error) {
+		return font, nil
+	})
+}
+
+// BenchmarkRenderClones renders from a clone of the font, in the font's own
+// context, in each goroutine.
+func BenchmarkRenderClones(b *testing.B) {
+	font := loadTestFont(b, "Vera.ttf")
+	benchmarkRender(b, func() (*Font, error) {
+		return font.Clone(font.ctx)
+	})
+}
+
+// BenchmarkRenderPool renders from a clone of the font, in a context of a
+// pool, in each goroutine.
+func BenchmarkRenderPool(b *testing.B) {
+	font := loadTestFont(b, "Vera.ttf")
+	var pool ContextPool
+	defer pool.Close()
+	benchmarkRender(b, func() (*Font, error) {
+		ctx, err := pool.Get()
+		if err != nil {
+			return nil, err
+		}
+		return font.Clone(ctx)
+	})
+}
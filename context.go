@@ -24,9 +24,8 @@ package freetype
 import "C"
 
 import (
-	"fmt"
 	"image"
-	"reflect"
+	"image/draw"
 	"runtime"
 	"sync"
 	"unsafe"
@@ -46,21 +45,34 @@ type GlyphMetrics struct {
 	Advance, UnhintedAdvance int
 }
 
-// GlyphImage is literally just *image.Alpha, except it must hold a pointer
-// internally to *Glyph to avoid a garbage collection invoking finalizers which
-// free the image data.
+// GlyphImage is an alpha image of a rendered glyph.
+//
+// The image's bounds are relative to the glyph origin (i.e. the pen position
+// on the baseline), with positive Y values extending downward. Thus the glyph
+// is drawn at the pen position p by:
+//
+//	b := img.Bounds()
+//	draw.DrawMask(dst, b.Add(p), src, image.Point{}, img, b.Min, draw.Over)
 type GlyphImage struct {
 	*image.Alpha
 
-	// Holds *Glyph to avoid GC.
+	// The glyph this image was rendered from.
 	glyph *Glyph
 }
 
 // Glyph represents a single renderable glyph.
 type Glyph struct {
 	// Holds *Font to avoid GC.
-	font        *Font
-	renderImage func(g *Glyph) (*GlyphImage, error)
+	font  *Font
+	index uint
+	opts  LoadOptions
+
+	// The size the glyph was loaded at, or nil for the font's own size.
+	size *Size
+
+	// The glyph's own copy of its data for persistent glyphs, otherwise nil
+	// and the glyph is (re)loaded into the font's glyph slot as needed.
+	c C.FT_Glyph
 
 	// Width and height of glyph.
 	// Expressed in font units.
@@ -70,15 +82,116 @@ type Glyph struct {
 	HMetrics, VMetrics GlyphMetrics
 }
 
-// Renders and returns a alpha image, it is returned as *GlyphImage because a
-// reference internally must be held to this *Glyph.
-//
-// The returned glyph image's data resides in a single slot held by the font
-// this glyph came from, as such you must ensure you only use a single
-// GlyphImage from the same font source at any given time (or make a copy of
-// the returned image).
+// Image renders the glyph using RenderNormal and returns the resulting alpha
+// image. The image is owned by the caller and remains valid after other glyphs
+// are loaded. Glyphs with other pixel formats (e.g. monochrome or color
+// embedded bitmaps) are converted to alpha.
 func (g *Glyph) Image() (*GlyphImage, error) {
-	return g.renderImage(g)
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	img, err := g.render(RenderNormal)
+	if err != nil {
+		return nil, err
+	}
+	alpha, ok := img.(*image.Alpha)
+	if !ok {
+		alpha = image.NewAlpha(img.Bounds())
+		draw.Draw(alpha, alpha.Rect, img, img.Bounds().Min, draw.Src)
+	}
+	return &GlyphImage{
+		glyph: g,
+		Alpha: alpha,
+	}, nil
+}
+
+// Copy returns a persistent copy of the glyph.
+//
+// Glyphs returned by Font.Load share the font's single glyph slot, and are
+// reloaded into it whenever they are used after another glyph was loaded. A
+// persistent glyph instead owns a copy of its outline (or bitmap) at the size
+// it was loaded with, and can be used concurrently with other glyphs.
+func (g *Glyph) Copy() (*Glyph, error) {
+	c := g.font.ctx
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	cpy := *g
+	var err error
+	cpy.c, err = g.glyphCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	c.glyphsAccess.Lock()
+	c.glyphs[cpy.c] = struct{}{}
+	c.glyphsAccess.Unlock()
+
+	runtime.SetFinalizer(&cpy, func(g *Glyph) {
+		c.access.RLock()
+		defer c.access.RUnlock()
+		c.glyphsAccess.Lock()
+		defer c.glyphsAccess.Unlock()
+
+		// The glyph was released already if the context has been closed.
+		if _, ok := c.glyphs[g.c]; ok {
+			delete(c.glyphs, g.c)
+			C.FT_Done_Glyph(g.c)
+		}
+	})
+	return &cpy, nil
+}
+
+// glyphCopy returns a copy of the glyph's data, which must be released using
+// FT_Done_Glyph. The font lock must be held.
+func (g *Glyph) glyphCopy() (C.FT_Glyph, error) {
+	var cpy C.FT_Glyph
+	if g.c != nil {
+		err := C.FT_Glyph_Copy(g.c, &cpy)
+		if err != 0 {
+			return nil, newGlyphError("FT_Glyph_Copy", int(err), g.index)
+		}
+		return cpy, nil
+	}
+	slot, err := g.slot()
+	if err != nil {
+		return nil, err
+	}
+	ftErr := C.FT_Get_Glyph(slot, &cpy)
+	if ftErr != 0 {
+		return nil, newGlyphError("FT_Get_Glyph", int(ftErr), g.index)
+	}
+	return cpy, nil
+}
+
+// slot returns the font's glyph slot, reloading this glyph into it first if
+// another glyph has been loaded (or this one rendered) since. The font
+// lock must be held.
+func (g *Glyph) slot() (C.FT_GlyphSlot, error) {
+	f := g.font
+	if f.slot != g {
+		if err := f.activate(g.size); err != nil {
+			return nil, err
+		}
+		err := C.FT_Load_Glyph(f.c, C.FT_UInt(g.index), g.opts.loadFlags())
+		if err != 0 {
+			return nil, f.newGlyphError("FT_Load_Glyph", int(err), g.index)
+		}
+		if err := g.synthesize(f.c.glyph, false); err != nil {
+			f.slot = nil
+			return nil, err
+		}
+		f.slot = g
+	}
+	return f.c.glyph, nil
 }
 
 // Font represents a single Freetype font.
@@ -89,6 +202,31 @@ type Font struct {
 	data []uint8
 	c    C.FT_Face
 
+	// The stream the face reads its font file from, or nil if it reads it from
+	// data.
+	stream *stream
+
+	// The memory mapping holding data, or nil if it is not mapped.
+	mapping *mapping
+
+	// Guards the face when the context allows faces to be used in parallel,
+	// see the Context documentation.
+	access sync.Mutex
+
+	// The glyph currently loaded into the face's glyph slot, or nil.
+	slot *Glyph
+
+	// The face's own size, used by the methods of the font as opposed to those
+	// of the sizes returned by NewSize.
+	size C.FT_Size
+
+	// Sets the font's own size as last requested by SetSize or SetSizePixels,
+	// or nil if neither was called, for Clone.
+	resize func(f *Font) (SizeMetrics, error)
+
+	// Whether the font has been closed.
+	closed bool
+
 	// Bounding box that is large enough to contain any glyph in the font face.
 	// Expressed in font units.
 	BBox image.Rectangle
@@ -127,18 +265,39 @@ type Font struct {
 	// The thickness for the underline of this font face.
 	// Expressed in font units.
 	UnderlineThickness int
+
+	// The family name of the face (e.g. "Bitstream Vera Sans"), which may be
+	// empty.
+	FamilyName string
+
+	// The style name of the face (e.g. "Bold Oblique"), which may be empty.
+	StyleName string
+
+	// The PostScript name of the face, which may be empty.
+	PostScriptName string
+
+	// The number of glyphs in the face.
+	NumGlyphs int
+
+	// Flags describing the properties of the face.
+	FaceFlags FaceFlags
+
+	// Flags describing the style of the face.
+	StyleFlags StyleFlags
+
+	// The index of the face within the font file, and the number of faces in
+	// the font file (greater than one for font collections).
+	FaceIndex, NumFaces int
 }
 
 func (f *Font) init() {
+	f.size = f.c.size
 	f.SetSize(24*64, 24*64, 72, 72)
 
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
+	f.lock()
+	defer f.unlock()
 
-	err := C.FT_Select_Charmap(f.c, C.FT_ENCODING_UNICODE)
-	if err != 0 {
-		fmt.Println("Font.init(): FT_Select_Charmap() failed!")
-	}
+	f.selectDefaultCharmap()
 
 	b := f.c.bbox
 	f.BBox = image.Rect(
@@ -156,20 +315,49 @@ func (f *Font) init() {
 	f.MaxAdvanceHeight = int(f.c.max_advance_height)
 	f.UnderlinePosition = int(f.c.underline_position)
 	f.UnderlineThickness = int(f.c.underline_thickness)
+
+	f.FamilyName = C.GoString(f.c.family_name)
+	f.StyleName = C.GoString(f.c.style_name)
+	f.PostScriptName = C.GoString(C.FT_Get_Postscript_Name(f.c))
+	f.NumGlyphs = int(f.c.num_glyphs)
+	f.FaceIndex = int(f.c.face_index & 0xFFFF)
+	f.NumFaces = int(f.c.num_faces)
+	f.FaceFlags = FaceFlags(f.c.face_flags)
+	f.StyleFlags = StyleFlags(f.c.style_flags & 0xFFFF)
 }
 
 // SetSize sets the current size of the font given 26.6 width and height units
-// and X/Y axis resolutions.
-func (f *Font) SetSize(width, height, xResolution, yResolution int) error {
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
+// and X/Y axis resolutions, and returns the metrics of the font at that size.
+func (f *Font) SetSize(width, height, xResolution, yResolution int) (SizeMetrics, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return SizeMetrics{}, err
+	}
+
+	resize := func(f *Font) (SizeMetrics, error) {
+		return f.setSize(width, height, xResolution, yResolution)
+	}
+	if err := f.activate(nil); err != nil {
+		return SizeMetrics{}, err
+	}
+	m, err := resize(f)
+	if err == nil {
+		f.resize = resize
+	}
+	return m, err
+}
 
+// setSize implements SetSize for the active size, the font lock must be
+// held.
+func (f *Font) setSize(width, height, xResolution, yResolution int) (SizeMetrics, error) {
 	if width < 0 || height < 0 {
 		panic("SetSize(): width < 0 || height < 0")
 	}
 
-	if xResolution < 0 || xResolution < 0 {
-		panic("SetSize(): width < 0 || height < 0")
+	if xResolution < 0 || yResolution < 0 {
+		panic("SetSize(): xResolution < 0 || yResolution < 0")
 	}
 
 	err := C.FT_Set_Char_Size(
@@ -180,17 +368,37 @@ func (f *Font) SetSize(width, height, xResolution, yResolution int) error {
 		C.FT_UInt(yResolution),
 	)
 	if err != 0 {
-		return lookupErr[int(err)]
+		return SizeMetrics{}, f.newError("FT_Set_Char_Size", int(err))
 	}
-	return nil
+	return f.sizeMetrics(), nil
 }
 
 // SetSizePixels sets the current size of the font given width and height pixel
-// based units.
-func (f *Font) SetSizePixels(width, height int) error {
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
+// based units, and returns the metrics of the font at that size.
+func (f *Font) SetSizePixels(width, height int) (SizeMetrics, error) {
+	f.lock()
+	defer f.unlock()
 
+	if err := f.checkOpen(); err != nil {
+		return SizeMetrics{}, err
+	}
+
+	resize := func(f *Font) (SizeMetrics, error) {
+		return f.setSizePixels(width, height)
+	}
+	if err := f.activate(nil); err != nil {
+		return SizeMetrics{}, err
+	}
+	m, err := resize(f)
+	if err == nil {
+		f.resize = resize
+	}
+	return m, err
+}
+
+// setSizePixels implements SetSizePixels for the active size, the font lock
+// must be held.
+func (f *Font) setSizePixels(width, height int) (SizeMetrics, error) {
 	if width < 0 || height < 0 {
 		panic("SetSizePixels(): width < 0 || height < 0")
 	}
@@ -201,27 +409,57 @@ func (f *Font) SetSizePixels(width, height int) error {
 		C.FT_UInt(height),
 	)
 	if err != 0 {
-		return lookupErr[int(err)]
+		return SizeMetrics{}, f.newError("FT_Set_Pixel_Sizes", int(err))
 	}
-	return nil
+	return f.sizeMetrics(), nil
+}
+
+// SizeMetrics returns the metrics of the font at its current size.
+func (f *Font) SizeMetrics() SizeMetrics {
+	f.lock()
+	defer f.unlock()
+
+	if f.checkOpen() != nil {
+		return SizeMetrics{}
+	}
+
+	return newSizeMetrics(&f.size.metrics)
 }
 
-// Index returns the glyph index for the given rune.
+// Index returns the glyph index for the given rune in the active charmap (see
+// SelectCharmap), or zero (the .notdef glyph) if the rune is not mapped.
 func (f *Font) Index(r rune) (glyphIndex uint) {
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
+	f.lock()
+	defer f.unlock()
 
-	return uint(C.FT_Get_Char_Index(f.c, C.FT_ULong(r)))
+	if f.checkOpen() != nil {
+		return 0
+	}
+
+	return uint(f.charIndex(r))
 }
 
 // Kerning returns the X/Y kerning pair for the left and right horizontally
 // aligned glyphs, or x=0, y=0, and a error.
 func (f *Font) Kerning(leftGlyph, rightGlyph rune) (x, y int, e error) {
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return 0, 0, err
+	}
+
+	if err := f.activate(nil); err != nil {
+		return 0, 0, err
+	}
+	return f.kerning(leftGlyph, rightGlyph)
+}
 
-	left := C.FT_Get_Char_Index(f.c, C.FT_ULong(leftGlyph))
-	right := C.FT_Get_Char_Index(f.c, C.FT_ULong(rightGlyph))
+// kerning implements Kerning at the active size, the font lock must be
+// held.
+func (f *Font) kerning(leftGlyph, rightGlyph rune) (x, y int, e error) {
+	left := f.charIndex(leftGlyph)
+	right := f.charIndex(rightGlyph)
 	if left == 0 || right == 0 {
 		return 0, 0, nil
 	}
@@ -235,60 +473,58 @@ func (f *Font) Kerning(leftGlyph, rightGlyph rune) (x, y int, e error) {
 		&vec,
 	)
 	if err != 0 {
-		return 0, 0, lookupErr[int(err)]
+		e := f.newGlyphError("FT_Get_Kerning", int(err), uint(right))
+		e.Rune = rightGlyph
+		return 0, 0, e
 	}
 	return int(vec.x), int(vec.y), nil
 }
 
 // Load loads the given glyph index into the font's glyph slot and returns the
-// glyph.
+// glyph. It is short-hand for:
+//
+//	f.LoadWithOptions(glyphIndex, nil)
 func (f *Font) Load(glyphIndex uint) (*Glyph, error) {
-	f.ctx.access.Lock()
-	defer f.ctx.access.Unlock()
-
-	err := C.FT_Load_Glyph(
-		f.c,
-		C.FT_UInt(glyphIndex),
-		C.FT_LOAD_DEFAULT|C.FT_LOAD_LINEAR_DESIGN,
-	)
-	if err != 0 {
-		return nil, lookupErr[int(err)]
-	}
+	return f.LoadWithOptions(glyphIndex, nil)
+}
 
-	g := f.c.glyph
+// LoadWithOptions loads the given glyph index into the font's glyph slot using
+// the given options and returns the glyph. If opts is nil then
+// DefaultLoadOptions are used.
+func (f *Font) LoadWithOptions(glyphIndex uint, opts *LoadOptions) (*Glyph, error) {
+	f.lock()
+	defer f.unlock()
 
-	renderImage := func(glyph *Glyph) (*GlyphImage, error) {
-		err = C.FT_Render_Glyph(g, C.FT_RENDER_MODE_NORMAL)
-		if err != 0 {
-			return nil, lookupErr[int(err)]
-		}
-
-		// The face's glyph slot will change, so we need to copy the bitmap.
-		width := int(g.bitmap.width)
-		height := int(g.bitmap.rows)
-		length := width * height
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
 
-		var data []uint8
-		sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&data))
-		sliceHeader.Cap = length
-		sliceHeader.Len = length
-		sliceHeader.Data = uintptr(unsafe.Pointer(g.bitmap.buffer))
+	return f.load(nil, glyphIndex, opts)
+}
 
-		img := image.NewAlpha(image.Rect(0, 0, width, height))
-		img.Pix = data
-		img.Stride = width
-		return &GlyphImage{
-			glyph: glyph,
-			Alpha: img,
-		}, nil
+// load implements LoadWithOptions at the given size, or the font's own size if
+// it is nil. The font lock must be held.
+func (f *Font) load(size *Size, glyphIndex uint, opts *LoadOptions) (*Glyph, error) {
+	if opts == nil {
+		opts = &DefaultLoadOptions
+	}
+	if err := f.activate(size); err != nil {
+		return nil, err
+	}
+	err := C.FT_Load_Glyph(f.c, C.FT_UInt(glyphIndex), opts.loadFlags())
+	if err != 0 {
+		return nil, f.newGlyphError("FT_Load_Glyph", int(err), glyphIndex)
 	}
 
+	g := f.c.glyph
 	m := g.metrics
-	return &Glyph{
-		font:        f,
-		renderImage: renderImage,
-		Width:       int(m.width),
-		Height:      int(m.height),
+	glyph := &Glyph{
+		font:   f,
+		index:  glyphIndex,
+		opts:   *opts,
+		size:   size,
+		Width:  int(m.width),
+		Height: int(m.height),
 		HMetrics: GlyphMetrics{
 			BearingX:        int(m.horiBearingX),
 			BearingY:        int(m.horiBearingY),
@@ -301,55 +537,344 @@ func (f *Font) Load(glyphIndex uint) (*Glyph, error) {
 			Advance:         int(m.vertAdvance),
 			UnhintedAdvance: int(g.linearVertAdvance),
 		},
-	}, nil
+	}
+	if err := glyph.synthesize(g, true); err != nil {
+		f.slot = nil
+		return nil, err
+	}
+	f.slot = glyph
+	return glyph, nil
+}
+
+// Close releases the font's face and sizes, and its memory mapping (see
+// LoadMappedFile). It is safe to call Close more than once, and only the
+// mapping is released if the font's context was closed already, which released
+// the face.
+//
+// After the font is closed, methods of the font and of its sizes and glyphs
+// return ErrClosed (or zero values, for those which do not return an error).
+func (f *Font) Close() error {
+	// Faces are created and released under the context lock.
+	f.ctx.access.Lock()
+	defer f.ctx.access.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	f.slot = nil
+	runtime.SetFinalizer(f, nil)
+	if f.mapping != nil {
+		// The face must not be used after the mapping is released.
+		defer f.mapping.release()
+	}
+	if f.ctx.closed {
+		return nil
+	}
+
+	// This also releases the sizes of the face.
+	err := C.FT_Done_Face(f.c)
+	if err != 0 {
+		return newError("FT_Done_Face", int(err))
+	}
+	return nil
+}
+
+// Clone loads the font's face again into the given context, which may be the
+// font's own one. The font file data is shared rather than copied.
+//
+// The clone has the size and active charmap of the font, but not its variation
+// coordinates. Being a separate face with its own glyph slot, it can be used in
+// parallel with the font (see Context).
+func (f *Font) Clone(ctx *Context) (*Font, error) {
+	f.lock()
+	if err := f.checkOpen(); err != nil {
+		f.unlock()
+		return nil, err
+	}
+	data, faceIndex, resize := f.data, int(f.c.face_index), f.resize
+	var src *streamSource
+	if f.stream != nil {
+		src = f.stream.src
+	}
+	m := f.mapping
+	if m != nil {
+		// Keep the mapping while loading the clone.
+		m.acquire()
+		defer m.release()
+	}
+	f.unlock()
+
+	cm, hasCharmap := f.Charmap()
+
+	var (
+		clone *Font
+		err   error
+	)
+	switch {
+	case src != nil:
+		clone, err = ctx.loadStream(src, faceIndex)
+	case m != nil:
+		clone, err = ctx.loadMapping(m, faceIndex)
+	default:
+		clone, err = ctx.LoadFace(data, faceIndex)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hasCharmap {
+		if err := clone.SetCharmap(cm); err != nil {
+			clone.Close()
+			return nil, err
+		}
+	}
+	if resize != nil {
+		clone.lock()
+		_, err := resize(clone)
+		if err == nil {
+			clone.resize = resize
+		}
+		clone.unlock()
+
+		if err != nil {
+			clone.Close()
+			return nil, err
+		}
+	}
+	return clone, nil
 }
 
-// Context represents a single freetype context which must not be accessed
-// concurrently (typically each thread/goroutine uses a single context).
+// checkOpen returns ErrClosed if the font or its context has been closed. The
+// font lock must be held.
+func (f *Font) checkOpen() error {
+	if f.closed || f.ctx.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// lock acquires the font lock, which guards the font's face and must be held
+// while using it. If the context allows faces to be used in parallel then this
+// is a read lock of the context plus the font's own lock, otherwise it is the
+// context lock itself.
+func (f *Font) lock() {
+	if f.ctx.parallelFaces {
+		f.ctx.access.RLock()
+		f.access.Lock()
+		return
+	}
+	f.ctx.access.Lock()
+}
+
+// unlock releases the font lock.
+func (f *Font) unlock() {
+	if f.stream != nil {
+		// Read errors are only reported by the operation they occurred in.
+		f.stream.err = nil
+	}
+	if f.ctx.parallelFaces {
+		f.access.Unlock()
+		f.ctx.access.RUnlock()
+		return
+	}
+	f.ctx.access.Unlock()
+}
+
+// Context represents a single FreeType library instance, from which fonts are
+// loaded.
+//
+// All methods of a context and of its fonts, sizes and glyphs are safe for
+// concurrent use. How much actually runs in parallel depends on the version of
+// FreeType (see Version):
+//
+//   - Since FreeType 2.6 faces can be used in parallel, so each font has its own
+//     lock. Goroutines using different fonts, including clones of a single font
+//     (see Font.Clone), do not block each other, while those using a single
+//     font (or its sizes and glyphs) are serialised.
+//   - With older versions of FreeType the context has a single lock, and all
+//     use of its fonts is serialised. Parallel work then needs several contexts,
+//     see ContextPool.
+//
+// Loading and closing fonts, setting the LCD filter and closing the context
+// always wait for all other use of the context to finish.
 type Context struct {
-	access sync.Mutex
-	c      C.FT_Library
+	// Locked for writing by operations on the library itself, and for reading
+	// by those on faces if parallelFaces is true (see Font.lock).
+	access    sync.RWMutex
+	c         C.FT_Library
+	lcdFilter LcdFilter
+
+	// Whether faces of the library can be used in parallel, i.e. FreeType is
+	// version 2.6 or later.
+	parallelFaces bool
+
+	// Guards the library's LCD filter while rendering with it, since it may be
+	// shared by faces used in parallel.
+	lcdAccess sync.Mutex
+
+	// The persistent glyphs (see Glyph.Copy) which have not been released,
+	// guarded by glyphsAccess.
+	glyphs       map[C.FT_Glyph]struct{}
+	glyphsAccess sync.Mutex
+
+	// Whether the context has been closed.
+	closed bool
+}
+
+// Version returns the version of the FreeType library used by the context,
+// e.g. 2, 6 and 1 for FreeType 2.6.1.
+func (c *Context) Version() (major, minor, patch int) {
+	c.access.RLock()
+	defer c.access.RUnlock()
+
+	if c.closed {
+		return 0, 0, 0
+	}
+	return c.version()
+}
+
+// version implements Version, the context lock must be held for reading.
+func (c *Context) version() (major, minor, patch int) {
+	var ma, mi, pa C.FT_Int
+	C.FT_Library_Version(c.c, &ma, &mi, &pa)
+	return int(ma), int(mi), int(pa)
+}
+
+// Close releases the context along with all of its fonts, sizes and glyphs,
+// which thereafter return ErrClosed. It is safe to call Close more than once.
+func (c *Context) Close() error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	runtime.SetFinalizer(c, nil)
+
+	// Glyphs are not owned by the library, unlike faces (and their sizes)
+	// which FT_Done_FreeType releases.
+	c.glyphsAccess.Lock()
+	for g := range c.glyphs {
+		C.FT_Done_Glyph(g)
+	}
+	c.glyphs = nil
+	c.glyphsAccess.Unlock()
+
+	err := C.FT_Done_FreeType(c.c)
+	if err != 0 {
+		return newError("FT_Done_FreeType", int(err))
+	}
+	return nil
 }
 
 // Load loads and returns the given font file data and returns the loaded font
-// or an error.
+// or an error. It is short-hand for:
+//
+//	c.LoadFace(fontFileData, 0)
 func (c *Context) Load(fontFileData []byte) (*Font, error) {
+	return c.LoadFace(fontFileData, 0)
+}
+
+// LoadFace loads the face with the given index from the font file data and
+// returns the loaded font or an error. Font collections (e.g. TTC or OTC files)
+// contain several faces, see NumFaces.
+//
+// The font file data is not copied and must not be modified while the font is
+// in use, thus faces loaded from the same slice share a single copy of it.
+func (c *Context) LoadFace(fontFileData []byte, faceIndex int) (*Font, error) {
+	if faceIndex < 0 {
+		return nil, ErrInvalidArgument
+	}
+
 	c.access.Lock()
+	if c.closed {
+		c.access.Unlock()
+		return nil, ErrClosed
+	}
 
 	f := new(Font)
 	f.ctx = c
 	f.data = fontFileData
-	err := C.FT_New_Memory_Face(
-		c.c,
-		(*C.FT_Byte)(unsafe.Pointer(&f.data[0])),
-		C.FT_Long(len(f.data)),
-		0,
-		&f.c,
-	)
-	if err != 0 {
-		return nil, lookupErr[int(err)]
+	var err error
+	f.c, err = c.newMemoryFace(f.data, faceIndex)
+	if err != nil {
+		c.access.Unlock()
+		return nil, err
 	}
 
 	c.access.Unlock()
 
+	return c.newFont(f), nil
+}
+
+// newFont initializes the given font, whose face has just been opened, and
+// returns it. The context lock must not be held.
+func (c *Context) newFont(f *Font) *Font {
 	f.init()
 
 	runtime.SetFinalizer(f, func(f *Font) {
 		c.access.Lock()
 		defer c.access.Unlock()
 
-		C.FT_Done_Face(f.c)
+		if !c.closed {
+			C.FT_Done_Face(f.c)
+		}
+		if f.mapping != nil {
+			f.mapping.release()
+		}
 	})
-	return f, nil
+	return f
+}
+
+// NumFaces returns the number of faces in the given font file data, which is
+// greater than one for font collections (e.g. TTC or OTC files).
+func (c *Context) NumFaces(fontFileData []byte) (int, error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return 0, ErrClosed
+	}
+
+	// A negative face index only tests the font format and fills in num_faces.
+	face, err := c.newMemoryFace(fontFileData, -1)
+	if err != nil {
+		return 0, err
+	}
+	defer C.FT_Done_Face(face)
+	return int(face.num_faces), nil
+}
+
+// newMemoryFace opens the face with the given index from the font file data.
+// The context lock must be held.
+func (c *Context) newMemoryFace(data []byte, faceIndex int) (C.FT_Face, error) {
+	if len(data) == 0 {
+		return nil, ErrUnknownFileFormat
+	}
+	var face C.FT_Face
+	err := C.FT_New_Memory_Face(
+		c.c,
+		(*C.FT_Byte)(unsafe.Pointer(&data[0])),
+		C.FT_Long(len(data)),
+		C.FT_Long(faceIndex),
+		&face,
+	)
+	if err != 0 {
+		return nil, newError("FT_New_Memory_Face", int(err))
+	}
+	return face, nil
 }
 
 // Init initializes and returns a new freetype context, or returns a error.
 func Init() (*Context, error) {
-	c := new(Context)
+	c := &Context{glyphs: make(map[C.FT_Glyph]struct{})}
 	err := C.FT_Init_FreeType(&c.c)
 	if err != 0 {
-		return nil, lookupErr[int(err)]
+		return nil, newError("FT_Init_FreeType", int(err))
 	}
+	major, minor, _ := c.version()
+	c.parallelFaces = major > 2 || major == 2 && minor >= 6
 
 	runtime.SetFinalizer(c, func(c *Context) {
 		c.access.Lock()
@@ -0,0 +1,277 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+*/
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+// CharIterator iterates over every character mapped by the active charmap of
+// a font, in ascending order of character codes:
+//
+//	it := font.Chars()
+//	for it.Next() {
+//		fmt.Println(it.Rune(), it.Index())
+//	}
+//
+// The iteration is undefined if the active charmap changes during it.
+type CharIterator struct {
+	f        *Font
+	started  bool
+	charcode C.FT_ULong
+	index    C.FT_UInt
+
+	// Whether the charmap being iterated is an Apple Roman one.
+	appleRoman bool
+}
+
+// Chars returns an iterator over the characters mapped by the active charmap of
+// the font.
+func (f *Font) Chars() *CharIterator {
+	return &CharIterator{f: f}
+}
+
+// Next advances the iterator to the next character, it returns false once
+// there are no more characters.
+func (it *CharIterator) Next() bool {
+	it.f.lock()
+	defer it.f.unlock()
+
+	if it.f.checkOpen() != nil {
+		return false
+	}
+
+	if it.started && it.index == 0 {
+		return false
+	}
+	it.next()
+	return it.index != 0
+}
+
+// next implements Next, the font lock must be held.
+func (it *CharIterator) next() {
+	if !it.started {
+		cm := it.f.c.charmap
+		it.appleRoman = cm != nil && cm.encoding == C.FT_ENCODING_APPLE_ROMAN
+		it.started = true
+		it.charcode = C.FT_Get_First_Char(it.f.c, &it.index)
+		return
+	}
+	it.charcode = C.FT_Get_Next_Char(it.f.c, it.charcode, &it.index)
+}
+
+// Rune returns the current character. Character codes of Apple Roman charmaps
+// are converted to Unicode, those of other charmaps are returned as-is.
+func (it *CharIterator) Rune() rune {
+	code := rune(it.charcode)
+	if it.appleRoman && code >= 0x80 && code <= 0xFF {
+		return macRoman[code-0x80]
+	}
+	return code
+}
+
+// Index returns the glyph index of the current character.
+func (it *CharIterator) Index() uint {
+	return uint(it.index)
+}
+
+// RangeCoverage is the number of characters of a Unicode block or script that
+// a font covers.
+type RangeCoverage struct {
+	// The name of the block (e.g. "Greek and Coptic") or script (e.g.
+	// "Greek").
+	Name string
+
+	// The number of assigned characters of the range mapped by the font, and
+	// the total number of assigned characters in the range.
+	Covered, Total int
+}
+
+// Complete tells if every assigned character of the range is covered.
+func (r RangeCoverage) Complete() bool {
+	return r.Covered >= r.Total
+}
+
+// String returns a string like "Cyrillic: 256/256".
+func (r RangeCoverage) String() string {
+	return fmt.Sprintf("%s: %d/%d", r.Name, r.Covered, r.Total)
+}
+
+// Coverage summarises which Unicode blocks and scripts a font covers.
+type Coverage struct {
+	// The coverage of each Unicode block and script which the font covers at
+	// least partially. Blocks are in code point order, scripts in order of
+	// their names.
+	Blocks, Scripts []RangeCoverage
+
+	runes map[rune]bool
+}
+
+// Block returns the coverage of the named Unicode block (e.g. "Cyrillic"), or
+// a zero RangeCoverage if there is no such block.
+func (c *Coverage) Block(name string) RangeCoverage {
+	for _, b := range c.Blocks {
+		if b.Name == name {
+			return b
+		}
+	}
+	for _, b := range unicodeBlocks {
+		if b.name == name {
+			return RangeCoverage{Name: name, Total: blockTotal(b)}
+		}
+	}
+	return RangeCoverage{}
+}
+
+// Script returns the coverage of the named Unicode script (e.g. "Cyrillic"),
+// as named by the unicode package, or a zero RangeCoverage if there is no such
+// script.
+func (c *Coverage) Script(name string) RangeCoverage {
+	for _, s := range c.Scripts {
+		if s.Name == name {
+			return s
+		}
+	}
+	if t, ok := unicode.Scripts[name]; ok {
+		return RangeCoverage{Name: name, Total: tableTotal(t)}
+	}
+	return RangeCoverage{}
+}
+
+// Has tells if the given rune is covered.
+func (c *Coverage) Has(r rune) bool {
+	return c.runes[r]
+}
+
+// String returns a report of the coverage, one block or script per line.
+func (c *Coverage) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("Blocks:\n")
+	for _, b := range c.Blocks {
+		fmt.Fprintf(&buf, "\t%v\n", b)
+	}
+	buf.WriteString("Scripts:\n")
+	for _, s := range c.Scripts {
+		fmt.Fprintf(&buf, "\t%v\n", s)
+	}
+	return buf.String()
+}
+
+// Coverage builds a report of the Unicode blocks and scripts the active
+// charmap of the font covers. Only assigned characters, other than control
+// characters, are considered.
+//
+// Which characters are assigned, and their scripts, are those of the Unicode
+// version of the unicode package (see unicode.Version), whereas the blocks are
+// those of Unicode 14.0.0: characters of blocks added since are not part of any
+// block.
+func (f *Font) Coverage() *Coverage {
+	f.lock()
+	if f.checkOpen() != nil {
+		f.unlock()
+		return &Coverage{}
+	}
+	it := f.Chars()
+	var runes []rune
+	for it.next(); it.index != 0; it.next() {
+		if r := it.Rune(); isAssigned(r) {
+			runes = append(runes, r)
+		}
+	}
+	f.unlock()
+
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	c := &Coverage{runes: make(map[rune]bool, len(runes))}
+	blocks := make(map[string]int)
+	scripts := make(map[string]int)
+	var script string
+	for _, r := range runes {
+		c.runes[r] = true
+
+		bi := sort.Search(len(unicodeBlocks), func(i int) bool {
+			return unicodeBlocks[i].hi >= r
+		})
+		if bi < len(unicodeBlocks) && unicodeBlocks[bi].lo <= r {
+			b := unicodeBlocks[bi]
+			if _, ok := blocks[b.name]; !ok {
+				c.Blocks = append(c.Blocks, RangeCoverage{Name: b.name, Total: blockTotal(b)})
+			}
+			blocks[b.name]++
+		}
+
+		// Neighbouring runes are likely of the same script, so try it first.
+		if script == "" || !unicode.Is(unicode.Scripts[script], r) {
+			script = ""
+			for name, t := range unicode.Scripts {
+				if unicode.Is(t, r) {
+					script = name
+					break
+				}
+			}
+		}
+		if script != "" {
+			scripts[script]++
+		}
+	}
+	for i := range c.Blocks {
+		c.Blocks[i].Covered = blocks[c.Blocks[i].Name]
+	}
+	for name, n := range scripts {
+		c.Scripts = append(c.Scripts, RangeCoverage{
+			Name:    name,
+			Covered: n,
+			Total:   tableTotal(unicode.Scripts[name]),
+		})
+	}
+	sort.Slice(c.Scripts, func(i, j int) bool { return c.Scripts[i].Name < c.Scripts[j].Name })
+	return c
+}
+
+// isAssigned tells if the rune is an assigned Unicode character, ignoring
+// control characters and surrogates which fonts do not map.
+func isAssigned(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Z, unicode.Cf, unicode.Co)
+}
+
+// blockTotal returns the number of assigned characters in the block.
+func blockTotal(b unicodeBlock) int {
+	n := 0
+	for r := b.lo; r <= b.hi; r++ {
+		if isAssigned(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// tableTotal returns the number of assigned characters in the range table, as
+// defined by isAssigned.
+func tableTotal(t *unicode.RangeTable) int {
+	n := 0
+	for _, r := range t.R16 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if isAssigned(c) {
+				n++
+			}
+		}
+	}
+	for _, r := range t.R32 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if isAssigned(c) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"unicode"
+)
+
+func TestChars(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	n := 0
+	last := rune(-1)
+	it := font.Chars()
+	for it.Next() {
+		if it.Rune() <= last {
+			t.Fatalf("runes out of order: %U after %U", it.Rune(), last)
+		}
+		last = it.Rune()
+		if want := font.Index(it.Rune()); it.Index() != want {
+			t.Fatalf("%U: Index %d, want %d", it.Rune(), it.Index(), want)
+		}
+		n++
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after the end")
+	}
+	if n < 200 || n > font.NumGlyphs {
+		t.Fatalf("iterated %d characters, font has %d glyphs", n, font.NumGlyphs)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	cov := font.Coverage()
+	t.Log(cov)
+
+	if b := cov.Block("Basic Latin"); !b.Complete() || b.Total != 95 {
+		t.Errorf("%v, want 95/95", b)
+	}
+	if s := cov.Script("Latin"); s.Covered == 0 || s.Complete() {
+		t.Errorf("%v, want partial coverage", s)
+	}
+	if s := cov.Script("Cyrillic"); s.Covered != 0 || s.Total == 0 {
+		t.Errorf("%v, want no coverage", s)
+	}
+	if b := cov.Block("Greek and Coptic"); b.Total != 135 {
+		t.Errorf("%v, want a total of 135", b)
+	}
+	if b := cov.Block("No Such Block"); b != (RangeCoverage{}) {
+		t.Errorf("got %v for a missing block", b)
+	}
+	if !cov.Has('A') || cov.Has('Ж') {
+		t.Error("Has reports the wrong coverage")
+	}
+	for i := 1; i < len(cov.Blocks); i++ {
+		if cov.Blocks[i-1].Name == cov.Blocks[i].Name {
+			t.Errorf("block %q listed twice", cov.Blocks[i].Name)
+		}
+	}
+}
+
+// coverageBDF returns a BDF font with a blank glyph for every assigned
+// character of the given range tables.
+func coverageBDF(tables ...*unicode.RangeTable) []byte {
+	var runes []rune
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if isAssigned(r) && unicode.In(r, tables...) {
+			runes = append(runes, r)
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteString("STARTFONT 2.1\nFONT -test-coverage-medium-r-normal--8-80-75-75-c-80-iso10646-1\nSIZE 8 75 75\nFONTBOUNDINGBOX 8 8 0 0\n")
+	fmt.Fprintf(&buf, "CHARS %d\n", len(runes))
+	for _, r := range runes {
+		fmt.Fprintf(&buf, "STARTCHAR u%04X\nENCODING %d\nSWIDTH 1000 0\nDWIDTH 8 0\nBBX 1 1 0 0\nBITMAP\n80\nENDCHAR\n", r, r)
+	}
+	buf.WriteString("ENDFONT\n")
+	return buf.Bytes()
+}
+
+func TestCoverageComplete(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.Load(coverageBDF(unicode.Common, unicode.Ogham))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := font.Coverage()
+
+	// Scripts and blocks only count assigned characters, so that those holding
+	// control or unassigned characters can be complete.
+	for _, name := range []string{"Common", "Ogham"} {
+		if s := cov.Script(name); !s.Complete() || s.Covered != s.Total {
+			t.Errorf("%v, want complete coverage", s)
+		}
+	}
+	for _, name := range []string{"Arrows", "Ogham"} {
+		if b := cov.Block(name); !b.Complete() || b.Covered != b.Total {
+			t.Errorf("%v, want complete coverage", b)
+		}
+	}
+	if s := cov.Script("Latin"); s.Covered != 0 {
+		t.Errorf("%v, want no coverage", s)
+	}
+}
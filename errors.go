@@ -6,6 +6,7 @@ package freetype
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -100,6 +101,11 @@ var (
 	ErrCorruptedFontHeader         = errors.New("Font header corrupted or missing fields")
 	ErrCorruptedFontGlyphs         = errors.New("Font glyphs corrupted or missing fields")
 
+	// Errors which are not FreeType error codes.
+	ErrNoGlyphNames = errors.New("font has no glyph names")
+	ErrNoAxes       = errors.New("font has no variation axes")
+	ErrClosed       = errors.New("font or context is closed")
+
 	lookupErr = map[int]error{
 		0x01: ErrCannotOpenResource,
 		0x02: ErrUnknownFileFormat,
@@ -193,3 +199,73 @@ var (
 		0xBA: ErrCorruptedFontGlyphs,
 	}
 )
+
+// Error is an error returned by a FreeType function. It wraps the Err*
+// variable matching its code, if any, so that for example:
+//
+//	errors.Is(err, ErrInvalidGlyphIndex)
+//
+// reports whether err is a FreeType invalid glyph index error.
+type Error struct {
+	// The FreeType error code, e.g. 0x10 for an invalid glyph index.
+	Code int
+
+	// The FreeType function which failed, e.g. "FT_Load_Glyph".
+	Op string
+
+	// The glyph index and rune the function was called for, or -1 if not
+	// known.
+	Glyph int
+	Rune  rune
+
+	// The error which caused the FreeType error, or nil. This is the error of
+	// the reader for fonts loaded using LoadReaderAt or LoadFile, in which case
+	// Code is that of ErrInvalidStreamRead.
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := "unknown error"
+	if err := lookupErr[e.Code]; err != nil {
+		msg = err.Error()
+	}
+	s := fmt.Sprintf("%s: %s (error 0x%02x", e.Op, msg, e.Code)
+	if e.Glyph >= 0 {
+		s += fmt.Sprintf(", glyph %d", e.Glyph)
+	}
+	if e.Rune >= 0 {
+		s += fmt.Sprintf(", rune %U", e.Rune)
+	}
+	s += ")"
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the Err* variable matching the error code, if any, and the
+// error which caused the FreeType error, if any.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if err := lookupErr[e.Code]; err != nil {
+		errs = append(errs, err)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// newError returns an error for the given FreeType function and non-zero error
+// code.
+func newError(op string, code int) *Error {
+	return &Error{Code: code, Op: op, Glyph: -1, Rune: -1}
+}
+
+// newGlyphError is like newError, except the error is for the given glyph
+// index.
+func newGlyphError(op string, code int, glyphIndex uint) *Error {
+	e := newError(op, code)
+	e.Glyph = int(glyphIndex)
+	return e
+}
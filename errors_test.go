@@ -0,0 +1,61 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	err := error(newGlyphError("FT_Load_Glyph", 0x10, 1234))
+	if !errors.Is(err, ErrInvalidGlyphIndex) {
+		t.Errorf("errors.Is(%v, ErrInvalidGlyphIndex) = false", err)
+	}
+	if errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("errors.Is(%v, ErrInvalidArgument) = true", err)
+	}
+	want := "FT_Load_Glyph: invalid glyph index (error 0x10, glyph 1234)"
+	if got := err.Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Codes without a matching variable must still produce an error.
+	unknown := newError("FT_Get_Kerning", 0xFF)
+	unknown.Rune = 'A'
+	if errs := unknown.Unwrap(); len(errs) != 0 {
+		t.Errorf("Unwrap() = %v, want none", errs)
+	}
+	want = "FT_Get_Kerning: unknown error (error 0xff, rune U+0041)"
+	if got := unknown.Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Codes are printed with two digits.
+	want = "FT_Stroker_New: invalid argument (error 0x06)"
+	if got := newError("FT_Stroker_New", 0x06).Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 12); err != nil {
+		t.Fatal(err)
+	}
+
+	index := uint(font.NumGlyphs + 10)
+	_, err := font.Load(index)
+	var ftErr *Error
+	if !errors.As(err, &ftErr) {
+		t.Fatalf("got error %v (%T), want *Error", err, err)
+	}
+	if ftErr.Op != "FT_Load_Glyph" || ftErr.Glyph != int(index) || ftErr.Rune != -1 {
+		t.Errorf("got %+v", *ftErr)
+	}
+	if errs := ftErr.Unwrap(); len(errs) != 1 || !errors.Is(err, errs[0]) {
+		t.Errorf("%v does not wrap a known error", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+*/
+import "C"
+
+// FaceFlags describes properties of a font face, they map onto FreeType's
+// FT_FACE_FLAG_* flags.
+type FaceFlags int64
+
+const (
+	// FaceScalable is set for faces with outline glyphs.
+	FaceScalable FaceFlags = C.FT_FACE_FLAG_SCALABLE
+
+	// FaceFixedSizes is set for faces with embedded bitmap strikes.
+	FaceFixedSizes FaceFlags = C.FT_FACE_FLAG_FIXED_SIZES
+
+	// FaceFixedWidth is set for faces whose glyphs all have the same advance
+	// width (i.e. monospace faces).
+	FaceFixedWidth FaceFlags = C.FT_FACE_FLAG_FIXED_WIDTH
+
+	// FaceSFNT is set for TrueType and OpenType faces.
+	FaceSFNT FaceFlags = C.FT_FACE_FLAG_SFNT
+
+	// FaceHorizontal is set for faces with horizontal metrics.
+	FaceHorizontal FaceFlags = C.FT_FACE_FLAG_HORIZONTAL
+
+	// FaceVertical is set for faces with vertical metrics.
+	FaceVertical FaceFlags = C.FT_FACE_FLAG_VERTICAL
+
+	// FaceKerning is set for faces with kerning data.
+	FaceKerning FaceFlags = C.FT_FACE_FLAG_KERNING
+
+	// FaceMultipleMasters is set for Multiple Master and variable faces.
+	FaceMultipleMasters FaceFlags = C.FT_FACE_FLAG_MULTIPLE_MASTERS
+
+	// FaceGlyphNames is set for faces with PostScript glyph names.
+	FaceGlyphNames FaceFlags = C.FT_FACE_FLAG_GLYPH_NAMES
+
+	// FaceHinter is set for faces with their own hinting instructions.
+	FaceHinter FaceFlags = C.FT_FACE_FLAG_HINTER
+
+	// FaceCIDKeyed is set for CID-keyed faces.
+	FaceCIDKeyed FaceFlags = C.FT_FACE_FLAG_CID_KEYED
+
+	// FaceTricky is set for faces which can only be rendered correctly using
+	// their own hinting instructions.
+	FaceTricky FaceFlags = C.FT_FACE_FLAG_TRICKY
+)
+
+// StyleFlags describes the style of a font face, they map onto FreeType's
+// FT_STYLE_FLAG_* flags.
+type StyleFlags int64
+
+const (
+	// StyleItalic is set for italic (or oblique) faces.
+	StyleItalic StyleFlags = C.FT_STYLE_FLAG_ITALIC
+
+	// StyleBold is set for bold faces.
+	StyleBold StyleFlags = C.FT_STYLE_FLAG_BOLD
+)
+
+// IsScalable tells if the font has scalable outlines.
+func (f *Font) IsScalable() bool {
+	return f.FaceFlags&FaceScalable != 0
+}
+
+// IsFixedWidth tells if all glyphs of the font have the same advance width.
+func (f *Font) IsFixedWidth() bool {
+	return f.FaceFlags&FaceFixedWidth != 0
+}
+
+// IsSFNT tells if the font is a TrueType or OpenType font.
+func (f *Font) IsSFNT() bool {
+	return f.FaceFlags&FaceSFNT != 0
+}
+
+// HasKerning tells if the font has kerning data for use with Font.Kerning.
+func (f *Font) HasKerning() bool {
+	return f.FaceFlags&FaceKerning != 0
+}
+
+// HasVertical tells if the font has vertical metrics.
+func (f *Font) HasVertical() bool {
+	return f.FaceFlags&FaceVertical != 0
+}
+
+// HasFixedSizes tells if the font has embedded bitmap strikes.
+func (f *Font) HasFixedSizes() bool {
+	return f.FaceFlags&FaceFixedSizes != 0
+}
+
+// IsBold tells if the font is bold.
+func (f *Font) IsBold() bool {
+	return f.StyleFlags&StyleBold != 0
+}
+
+// IsItalic tells if the font is italic.
+func (f *Font) IsItalic() bool {
+	return f.StyleFlags&StyleItalic != 0
+}
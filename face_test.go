@@ -0,0 +1,49 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import "testing"
+
+func TestFaceMetadata(t *testing.T) {
+	tests := []struct {
+		file, family, style, postScript   string
+		bold, italic, fixedWidth, kerning bool
+	}{
+		{"Vera.ttf", "Bitstream Vera Sans", "Roman", "BitstreamVeraSans-Roman", false, false, false, true},
+		{"VeraBI.ttf", "Bitstream Vera Sans", "Bold Oblique", "BitstreamVeraSans-BoldOblique", true, true, false, true},
+		{"VeraMono.ttf", "Bitstream Vera Sans Mono", "Roman", "BitstreamVeraSansMono-Roman", false, false, true, false},
+		{"VeraSeBd.ttf", "Bitstream Vera Serif", "Bold", "BitstreamVeraSerif-Bold", true, false, false, true},
+	}
+	for _, tst := range tests {
+		font := loadTestFont(t, tst.file)
+		if font.FamilyName != tst.family {
+			t.Errorf("%s: FamilyName %q, want %q", tst.file, font.FamilyName, tst.family)
+		}
+		if font.StyleName != tst.style {
+			t.Errorf("%s: StyleName %q, want %q", tst.file, font.StyleName, tst.style)
+		}
+		if font.PostScriptName != tst.postScript {
+			t.Errorf("%s: PostScriptName %q, want %q", tst.file, font.PostScriptName, tst.postScript)
+		}
+		if font.IsBold() != tst.bold {
+			t.Errorf("%s: IsBold() = %v, want %v", tst.file, font.IsBold(), tst.bold)
+		}
+		if font.IsItalic() != tst.italic {
+			t.Errorf("%s: IsItalic() = %v, want %v", tst.file, font.IsItalic(), tst.italic)
+		}
+		if font.IsFixedWidth() != tst.fixedWidth {
+			t.Errorf("%s: IsFixedWidth() = %v, want %v", tst.file, font.IsFixedWidth(), tst.fixedWidth)
+		}
+		if font.HasKerning() != tst.kerning {
+			t.Errorf("%s: HasKerning() = %v, want %v", tst.file, font.HasKerning(), tst.kerning)
+		}
+		if !font.IsScalable() || !font.IsSFNT() {
+			t.Errorf("%s: FaceFlags %#x missing scalable or SFNT", tst.file, font.FaceFlags)
+		}
+		if font.NumGlyphs <= 0 {
+			t.Errorf("%s: NumGlyphs %d", tst.file, font.NumGlyphs)
+		}
+	}
+}
@@ -102,3 +102,23 @@ func TestFreetype(t *testing.T) {
 	}
 	t.Log("Wrote test_freetype_out.png file.")
 }
+
+// loadTestFont initializes a new context and loads the named font file from
+// the vera directory.
+func loadTestFont(t testing.TB, name string) *Font {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fontFileData, err := ioutil.ReadFile("vera/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	font, err := ctx.Load(fontFileData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
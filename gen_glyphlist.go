@@ -0,0 +1,76 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// This program generates glyphlist.go from the Adobe Glyph List in
+// glyphlist.txt. Run it with:
+//
+//	go run gen_glyphlist.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	f, err := os.Open("glyphlist.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	buf.WriteString(`// Code generated by gen_glyphlist.go from glyphlist.txt; DO NOT EDIT.
+
+package freetype
+
+// glyphList maps the names of the Adobe Glyph List to the characters they
+// represent. A few names map to a sequence of characters.
+var glyphList = map[string]string{
+`)
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		fields := strings.Split(text, ";")
+		if len(fields) != 2 || fields[0] == "" {
+			log.Fatalf("glyphlist.txt:%d: malformed line %q", line, text)
+		}
+		var runes []rune
+		for _, hex := range strings.Fields(fields[1]) {
+			r, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil {
+				log.Fatalf("glyphlist.txt:%d: %v", line, err)
+			}
+			runes = append(runes, rune(r))
+		}
+		if len(runes) == 0 {
+			log.Fatalf("glyphlist.txt:%d: no code points for %q", line, fields[0])
+		}
+		fmt.Fprintf(&buf, "\t%q: %s,\n", fields[0], strconv.QuoteToASCII(string(runes)))
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("glyphlist.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <stdlib.h>
+#include <ft2build.h>
+#include FT_FREETYPE_H
+*/
+import "C"
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+//go:generate go run gen_glyphlist.go
+
+// GlyphName returns the PostScript name of the given glyph index (e.g.
+// "uni0041" or "arrowright").
+//
+// If the font has no glyph names then ErrNoGlyphNames is returned.
+func (f *Font) GlyphName(glyphIndex uint) (string, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return "", err
+	}
+
+	if f.c.face_flags&C.FT_FACE_FLAG_GLYPH_NAMES == 0 {
+		return "", ErrNoGlyphNames
+	}
+	var buf [256]C.char
+	err := C.FT_Get_Glyph_Name(f.c, C.FT_UInt(glyphIndex), C.FT_Pointer(&buf[0]), C.FT_UInt(len(buf)))
+	if err != 0 {
+		return "", f.newGlyphError("FT_Get_Glyph_Name", int(err), glyphIndex)
+	}
+	return C.GoString(&buf[0]), nil
+}
+
+// GlyphIndexByName returns the glyph index of the glyph with the given
+// PostScript name, or zero (the .notdef glyph) if there is no such glyph.
+//
+// If the font has no glyph names, or none matching, the name is instead mapped
+// to a rune following the Adobe Glyph List rules (see NameRunes) which is then
+// looked up using the active charmap.
+func (f *Font) GlyphIndexByName(name string) uint {
+	f.lock()
+	defer f.unlock()
+
+	if f.checkOpen() != nil {
+		return 0
+	}
+
+	if f.c.face_flags&C.FT_FACE_FLAG_GLYPH_NAMES != 0 {
+		cname := C.CString(name)
+		index := C.FT_Get_Name_Index(f.c, (*C.FT_String)(cname))
+		C.free(unsafe.Pointer(cname))
+		if index != 0 || name == ".notdef" {
+			return uint(index)
+		}
+	}
+
+	// Ligatures (e.g. "f_i") cannot be found through the charmap.
+	runes := NameRunes(name)
+	if len(runes) != 1 {
+		return 0
+	}
+	return uint(f.charIndex(runes[0]))
+}
+
+// NameRunes maps the given glyph name to a sequence of runes, following the
+// rules of the Adobe Glyph List specification:
+//
+//	"A"           -> U+0041
+//	"afii10017"   -> U+0410 (any name of the list, see glyphlist.txt)
+//	"Aacute.sc"   -> U+00C1 (suffixes after a period are dropped)
+//	"f_i"         -> U+0066 U+0069 (ligature components are split at '_')
+//	"uni20AC"     -> U+20AC
+//	"uni00410042" -> U+0041 U+0042
+//	"u1F600"      -> U+1F600
+//
+// Components which cannot be mapped are dropped, so nil is returned if no
+// component can be mapped.
+func NameRunes(name string) []rune {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	var runes []rune
+	for _, c := range strings.Split(name, "_") {
+		if s, ok := glyphList[c]; ok {
+			runes = append(runes, []rune(s)...)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(c, "uni") && len(c) > 3 && (len(c)-3)%4 == 0:
+			var seq []rune
+			for i := 3; i < len(c); i += 4 {
+				r, ok := parseGlyphNameHex(c[i : i+4])
+				if !ok || (r >= 0xD800 && r <= 0xDFFF) {
+					seq = nil
+					break
+				}
+				seq = append(seq, r)
+			}
+			runes = append(runes, seq...)
+
+		case strings.HasPrefix(c, "u") && len(c) >= 5 && len(c) <= 7:
+			r, ok := parseGlyphNameHex(c[1:])
+			if ok && (r < 0xD800 || (r > 0xDFFF && r <= 0x10FFFF)) {
+				runes = append(runes, r)
+			}
+		}
+	}
+	return runes
+}
+
+// parseGlyphNameHex parses the uppercase hexadecimal digits of a glyph name.
+func parseGlyphNameHex(s string) (rune, bool) {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'F') {
+			return 0, false
+		}
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	return rune(v), err == nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestGlyphName(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	for _, r := range "Aaé€" {
+		index := font.Index(r)
+		name, err := font.GlyphName(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "" {
+			t.Errorf("%q: empty glyph name", r)
+		}
+		if got := font.GlyphIndexByName(name); got != index {
+			t.Errorf("GlyphIndexByName(%q) = %d, want %d", name, got, index)
+		}
+	}
+	if name, _ := font.GlyphName(font.Index('A')); name != "A" {
+		t.Errorf("GlyphName(Index('A')) = %q, want \"A\"", name)
+	}
+	if got := font.GlyphIndexByName("nosuchglyph"); got != 0 {
+		t.Errorf("GlyphIndexByName(\"nosuchglyph\") = %d, want 0", got)
+	}
+}
+
+func TestGlyphNameFallback(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A version 3 post table holds no glyph names.
+	post := sfntTable(t, data, "post")
+	binary.BigEndian.PutUint32(post, 0x00030000)
+
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.GlyphName(font.Index('A')); err != ErrNoGlyphNames {
+		t.Fatalf("GlyphName: got error %v, want %v", err, ErrNoGlyphNames)
+	}
+
+	tests := []struct {
+		name string
+		want rune
+	}{
+		{"A", 'A'},
+		{"eacute", 'é'},
+		{"uni00E9", 'é'},
+		{"u20AC", '€'},
+		{"Euro.alt", '€'},
+	}
+	for _, tst := range tests {
+		want := font.Index(tst.want)
+		if want == 0 {
+			t.Fatalf("no glyph for %q", tst.want)
+		}
+		if got := font.GlyphIndexByName(tst.name); got != want {
+			t.Errorf("GlyphIndexByName(%q) = %d, want %d", tst.name, got, want)
+		}
+	}
+	if got := font.GlyphIndexByName("nosuchglyph"); got != 0 {
+		t.Errorf("GlyphIndexByName(\"nosuchglyph\") = %d, want 0", got)
+	}
+}
+
+func TestNameRunes(t *testing.T) {
+	tests := []struct {
+		name string
+		want []rune
+	}{
+		{"A", []rune{'A'}},
+		{"Aacute.sc", []rune{'Á'}},
+		{"f_i", []rune{'f', 'i'}},
+		{"uni20AC", []rune{0x20AC}},
+		{"uni00410042", []rune{'A', 'B'}},
+		{"u1F600", []rune{0x1F600}},
+		{"Omega", []rune{0x2126}},
+		{"uni20ac", nil},
+		{"uniD800", nil},
+		{"u110000", nil},
+		{".notdef", nil},
+		{"nosuchglyph", nil},
+
+		// Names of the Adobe Glyph List beyond the Adobe Glyph List for New
+		// Fonts, including those mapping to several characters.
+		{"Amacron", []rune{0x0100}},
+		{"Gbreve", []rune{0x011E}},
+		{"afii10017", []rune{0x0410}},
+		{"afii57636", []rune{0x20AA}},
+		{"controlSTX", []rune{0x0001}},
+		{"dalethatafpatah", []rune{0x05D3, 0x05B2}},
+		{"Gbreve_afii10017.sc", []rune{0x011E, 0x0410}},
+	}
+	for _, tst := range tests {
+		if got := NameRunes(tst.name); !reflect.DeepEqual(got, tst.want) {
+			t.Errorf("NameRunes(%q) = %U, want %U", tst.name, got, tst.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+*/
+import "C"
+
+// LoadFlags is a set of flags controlling how a glyph is loaded, they map
+// directly onto FreeType's FT_LOAD_* flags.
+type LoadFlags int32
+
+const (
+	// LoadDefault loads a scaled and hinted glyph, preferring embedded
+	// bitmaps over outlines if the font has any.
+	LoadDefault LoadFlags = C.FT_LOAD_DEFAULT
+
+	// LoadNoScale loads the glyph unscaled and unhinted, all metrics and
+	// outline points are then expressed in font units.
+	LoadNoScale LoadFlags = C.FT_LOAD_NO_SCALE
+
+	// LoadNoHinting disables hinting of the glyph outline.
+	LoadNoHinting LoadFlags = C.FT_LOAD_NO_HINTING
+
+	// LoadRender renders the glyph into a bitmap immediately after loading.
+	LoadRender LoadFlags = C.FT_LOAD_RENDER
+
+	// LoadNoBitmap ignores any embedded bitmap strikes and always loads the
+	// glyph outline.
+	LoadNoBitmap LoadFlags = C.FT_LOAD_NO_BITMAP
+
+	// LoadVerticalLayout loads the glyph for vertical text layout.
+	LoadVerticalLayout LoadFlags = C.FT_LOAD_VERTICAL_LAYOUT
+
+	// LoadForceAutohint uses FreeType's auto-hinter instead of the font's own
+	// hinting instructions.
+	LoadForceAutohint LoadFlags = C.FT_LOAD_FORCE_AUTOHINT
+
+	// LoadPedantic makes FreeType report errors on slightly broken glyphs
+	// instead of working around them.
+	LoadPedantic LoadFlags = C.FT_LOAD_PEDANTIC
+
+	// LoadNoRecurse does not load the sub-glyphs of composite glyphs.
+	LoadNoRecurse LoadFlags = C.FT_LOAD_NO_RECURSE
+
+	// LoadIgnoreTransform ignores any transform set on the face.
+	LoadIgnoreTransform LoadFlags = C.FT_LOAD_IGNORE_TRANSFORM
+
+	// LoadMonochrome renders monochrome bitmaps when combined with
+	// LoadRender.
+	LoadMonochrome LoadFlags = C.FT_LOAD_MONOCHROME
+
+	// LoadLinearDesign keeps the unhinted advances in font units instead of
+	// 16.16 pixel units.
+	LoadLinearDesign LoadFlags = C.FT_LOAD_LINEAR_DESIGN
+
+	// LoadNoAutohint never uses the auto-hinter, even for fonts without
+	// hinting instructions.
+	LoadNoAutohint LoadFlags = C.FT_LOAD_NO_AUTOHINT
+
+	// LoadColor loads embedded color bitmaps (e.g. emoji) as BGRA bitmaps.
+	LoadColor LoadFlags = C.FT_LOAD_COLOR
+)
+
+// LoadTarget selects the hinting algorithm that is used when loading a glyph,
+// they map onto FreeType's FT_LOAD_TARGET_* modes.
+type LoadTarget int32
+
+const (
+	// TargetNormal is the default hinting algorithm, optimized for standard
+	// gray-level rendering.
+	TargetNormal LoadTarget = C.FT_RENDER_MODE_NORMAL
+
+	// TargetLight is a lighter hinting algorithm which only snaps glyphs to
+	// the pixel grid vertically, preserving their original shapes.
+	TargetLight LoadTarget = C.FT_RENDER_MODE_LIGHT
+
+	// TargetMono is a strong hinting algorithm for monochrome output.
+	TargetMono LoadTarget = C.FT_RENDER_MODE_MONO
+
+	// TargetLCD hints for horizontally decimated LCD displays.
+	TargetLCD LoadTarget = C.FT_RENDER_MODE_LCD
+
+	// TargetLCDV hints for vertically decimated LCD displays.
+	TargetLCDV LoadTarget = C.FT_RENDER_MODE_LCD_V
+)
+
+// LoadOptions describes how a glyph is loaded by Font.LoadWithOptions.
+type LoadOptions struct {
+	// Flags to load the glyph with.
+	Flags LoadFlags
+
+	// Target hinting mode to load the glyph with.
+	Target LoadTarget
+
+	// Synthetic emboldening of the glyph, for fonts without a bold face: its
+	// strokes are widened by EmboldenX horizontally and EmboldenY vertically,
+	// and its metrics adjusted to match. Font.EmboldenStrength is a good
+	// strength, like that of FreeType's FT_GlyphSlot_Embolden. Embedded bitmaps
+	// are emboldened by whole pixels, see Glyph.Embolden.
+	// Expressed in 26.6 pixel units.
+	EmboldenX, EmboldenY int
+
+	// Synthetic slant of the glyph, for fonts without an italic face: each
+	// point of its outline is shifted horizontally by Oblique times its height
+	// above the baseline (e.g. the Oblique constant). Embedded bitmaps cannot be
+	// slanted.
+	Oblique float64
+}
+
+// DefaultLoadOptions are the options used by Font.Load.
+var DefaultLoadOptions = LoadOptions{
+	Flags:  LoadDefault | LoadLinearDesign,
+	Target: TargetNormal,
+}
+
+// loadFlags returns the FT_LOAD_* flags for FT_Load_Glyph.
+func (o *LoadOptions) loadFlags() C.FT_Int32 {
+	return C.FT_Int32(o.Flags) | C.FT_Int32(o.Target&15)<<16
+}
@@ -0,0 +1,64 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLoadWithOptions(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 13); err != nil {
+		t.Fatal(err)
+	}
+	index := font.Index('g')
+
+	load := func(opts *LoadOptions) (*Glyph, []byte) {
+		glyph, err := font.LoadWithOptions(index, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := glyph.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pix := make([]byte, len(img.Pix))
+		copy(pix, img.Pix)
+		return glyph, pix
+	}
+	// Every case keeps LoadLinearDesign, so that differences are not merely
+	// those of the units of the unhinted advance. Each is compared to the same
+	// options without its flag or target.
+	autohint := LoadOptions{Flags: LoadForceAutohint | LoadLinearDesign}
+	tests := []struct {
+		name       string
+		base, opts LoadOptions
+	}{
+		{"NoHinting", DefaultLoadOptions, LoadOptions{Flags: LoadNoHinting | LoadLinearDesign}},
+		{"ForceAutohint", DefaultLoadOptions, autohint},
+		{"NoScale", DefaultLoadOptions, LoadOptions{Flags: LoadNoScale | LoadLinearDesign}},
+		{"TargetLight", DefaultLoadOptions, LoadOptions{Flags: LoadLinearDesign, Target: TargetLight}},
+		{"TargetMono", DefaultLoadOptions, LoadOptions{Flags: LoadLinearDesign, Target: TargetMono}},
+
+		// The TrueType interpreter hints the same for LCD targets as for
+		// TargetNormal, only the auto-hinter snaps stems differently.
+		{"TargetLCD", autohint, LoadOptions{Flags: autohint.Flags, Target: TargetLCD}},
+		{"TargetLCDV", autohint, LoadOptions{Flags: autohint.Flags, Target: TargetLCDV}},
+	}
+	for _, tst := range tests {
+		base, basePix := load(&tst.base)
+		glyph, pix := load(&tst.opts)
+		sameMetrics := glyph.Width == base.Width &&
+			glyph.Height == base.Height &&
+			glyph.HMetrics == base.HMetrics
+		if sameMetrics && bytes.Equal(pix, basePix) {
+			t.Errorf("%s: metrics and image are identical to those without it", tst.name)
+		}
+	}
+
+	// LoadNoBitmap is not covered: Vera has no embedded bitmap strikes, and
+	// FreeType ignores the flag for bitmap-only fonts like testBDF.
+}
@@ -0,0 +1,93 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// LoadMappedFile loads the first face of the font file at the given path, which
+// is memory mapped read-only instead of being read into memory, and returns the
+// loaded font or an error. Several processes loading the same font file thus
+// share a single copy of it, and pages of it are only read in as FreeType
+// accesses them.
+//
+// The mapping is shared by clones of the font, and released once the font and
+// its clones are closed or garbage collected.
+//
+// The file must not be modified while it is mapped: accessing the parts of the
+// mapping past the end of a truncated file crashes the program.
+//
+// On systems other than Linux the file is read into memory instead.
+func (c *Context) LoadMappedFile(path string) (*Font, error) {
+	m, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// The font holds its own reference, if loading succeeds.
+	defer m.release()
+	return c.loadMapping(m, 0)
+}
+
+// loadMapping loads the face with the given index from the font file of the
+// mapping, see LoadMappedFile.
+func (c *Context) loadMapping(m *mapping, faceIndex int) (*Font, error) {
+	f, err := c.LoadFace(m.data, faceIndex)
+	if err != nil {
+		return nil, err
+	}
+	m.acquire()
+	f.mapping = m
+	return f, nil
+}
+
+// mapping is a memory mapped font file, shared by a font and its clones.
+type mapping struct {
+	data []byte
+	refs int32
+}
+
+// mapFile maps the file at the given path, returning a mapping with a single
+// reference.
+func mapFile(path string) (*mapping, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	// The mapping remains valid after the file is closed.
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		return nil, ErrUnknownFileFormat
+	}
+	if int64(int(size)) != size {
+		return nil, ErrArrayTooLarge
+	}
+	data, err := mmap(file, int(size))
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &mapping{data: data, refs: 1}, nil
+}
+
+// acquire adds a reference to the mapping.
+func (m *mapping) acquire() {
+	atomic.AddInt32(&m.refs, 1)
+}
+
+// release removes a reference from the mapping, unmapping it once there is
+// none left.
+func (m *mapping) release() {
+	if atomic.AddInt32(&m.refs, -1) == 0 {
+		munmap(m.data)
+		m.data = nil
+	}
+}
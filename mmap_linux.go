@@ -0,0 +1,20 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps size bytes of the file read-only.
+func mmap(file *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmap unmaps data returned by mmap.
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mapped reports whether the file at the given path is mapped into memory.
+func mapped(t *testing.T, path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maps, err := ioutil.ReadFile("/proc/self/maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(maps), "\n") {
+		if strings.HasSuffix(line, " "+abs) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMappedFileUnmap(t *testing.T) {
+	const path = "vera/VeraMono.ttf"
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	// Closing the font unmaps the file.
+	font, err := ctx.LoadMappedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mapped(t, path) {
+		t.Fatal("file is not mapped after loading")
+	}
+	clone, err := font.Clone(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.Close()
+	if !mapped(t, path) {
+		t.Fatal("file is not mapped while a clone is in use")
+	}
+	clone.Close()
+	if mapped(t, path) {
+		t.Fatal("file is mapped after closing the font and its clone")
+	}
+
+	// As does releasing it.
+	if _, err := ctx.LoadMappedFile(path); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; mapped(t, path); i++ {
+		if i == 100 {
+			t.Fatal("file is mapped after the font was garbage collected")
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// And closing it after its context.
+	font, err = ctx.LoadMappedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Close()
+	if !mapped(t, path) {
+		t.Fatal("file is not mapped after closing the context")
+	}
+	font.Close()
+	if mapped(t, path) {
+		t.Fatal("file is mapped after closing the font")
+	}
+}
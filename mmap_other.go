@@ -0,0 +1,28 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package freetype
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads size bytes of the file into memory, on systems where mapping is
+// not supported.
+func mmap(file *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// munmap releases data returned by mmap, which is left to the garbage
+// collector.
+func munmap(data []byte) error {
+	return nil
+}
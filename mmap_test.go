@@ -0,0 +1,159 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMappedFile(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	mem := loadTestFont(t, "VeraSe.ttf")
+	font, err := ctx.LoadMappedFile("vera/VeraSe.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if font.FamilyName != mem.FamilyName || font.NumGlyphs != mem.NumGlyphs {
+		t.Errorf("got %q with %d glyphs, want %q with %d", font.FamilyName, font.NumGlyphs, mem.FamilyName, mem.NumGlyphs)
+	}
+	want, err := renderRunes(mem, "Mapped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := font.Clone(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &clone.data[0] != &font.data[0] {
+		t.Error("clone does not share the mapping")
+	}
+	for _, f := range []*Font{font, clone} {
+		got, err := renderRunes(f, "Mapped")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if !bytes.Equal(got[i], want[i]) {
+				t.Errorf("glyph %d differs from that of the font loaded from memory", i)
+			}
+		}
+	}
+	if err := font.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := clone.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ctx.LoadMappedFile("vera/missing.ttf"); err == nil {
+		t.Error("loading a missing file succeeded")
+	}
+}
+
+func TestLoadMappedFileTruncated(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "empty.ttf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.LoadMappedFile(filepath.Join(dir, "empty.ttf")); !errors.Is(err, ErrUnknownFileFormat) {
+		t.Errorf("empty file: got error %v, want ErrUnknownFileFormat", err)
+	}
+
+	// Move the glyph data to the end of the file, so that the file can be
+	// truncated within it while keeping the other tables whole. The record of
+	// the old 'glyf' table is renamed.
+	glyf := append([]byte(nil), sfntTable(t, data, "glyf")...)
+	for i := 12; i < 12+16*int(binary.BigEndian.Uint16(data[4:])); i += 16 {
+		if string(data[i:i+4]) == "glyf" {
+			copy(data[i:], "xxxx")
+		}
+	}
+	data = addTable(data, "glyf", glyf)
+	header := 12 + 16*int(binary.BigEndian.Uint16(data[4:]))
+	start := len(data) - len(glyf)
+
+	// Note the glyphs which are not empty in the whole file.
+	whole, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var drawn []uint
+	for i := 0; i < whole.NumGlyphs; i++ {
+		g, err := whole.Load(uint(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Width > 0 {
+			drawn = append(drawn, uint(i))
+		}
+	}
+
+	loaded := 0
+	for _, n := range []int{12, header - 1, start / 2, start + len(glyf)/4, start + len(glyf)/2, len(data) - 1000} {
+		path := filepath.Join(dir, "truncated.ttf")
+		if err := ioutil.WriteFile(path, data[:n], 0644); err != nil {
+			t.Fatal(err)
+		}
+		font, err := ctx.LoadMappedFile(path)
+		switch {
+		case n < header:
+			// The table directory itself is truncated.
+			if err == nil {
+				t.Errorf("%d bytes: loading a truncated table directory succeeded", n)
+				font.Close()
+			}
+			continue
+		case n < start:
+			// Whether the font loads depends on the tables which are cut.
+			if err != nil {
+				continue
+			}
+		case err != nil:
+			t.Errorf("%d bytes: truncated glyph data: %v", n, err)
+			continue
+		}
+		loaded++
+
+		// Glyphs past the end of the file fail to load or are empty, FreeType
+		// may ignore the whole truncated table.
+		missing := 0
+		for _, i := range drawn {
+			g, err := font.Load(i)
+			if err == nil {
+				_, err = g.Image()
+			}
+			if err != nil || g.Width == 0 {
+				missing++
+			}
+		}
+		if missing == 0 {
+			t.Errorf("%d bytes: all glyphs loaded from truncated file", n)
+		}
+		font.Close()
+	}
+	if loaded == 0 {
+		t.Error("no truncated file loaded")
+	}
+}
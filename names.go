@@ -0,0 +1,350 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_SFNT_NAMES_H
+*/
+import "C"
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// NameID identifies the kind of string held by a name record.
+type NameID int
+
+const (
+	// NameCopyright is the copyright notice.
+	NameCopyright NameID = 0
+
+	// NameFamily is the font family name (e.g. "Bitstream Vera Sans"), limited to
+	// four styles per family.
+	NameFamily NameID = 1
+
+	// NameSubfamily is the font subfamily (style) name, e.g. "Bold".
+	NameSubfamily NameID = 2
+
+	// NameUniqueID is a unique identifier of the font.
+	NameUniqueID NameID = 3
+
+	// NameFullName is the full font name, e.g. "Bitstream Vera Sans Bold".
+	NameFullName NameID = 4
+
+	// NameVersion is the version string, e.g. "Version 1.10".
+	NameVersion NameID = 5
+
+	// NamePostScript is the PostScript name of the font.
+	NamePostScript NameID = 6
+
+	// NameTrademark is the trademark notice.
+	NameTrademark NameID = 7
+
+	// NameManufacturer is the name of the font vendor.
+	NameManufacturer NameID = 8
+
+	// NameDesigner is the name of the designer of the typeface.
+	NameDesigner NameID = 9
+
+	// NameDescription is a description of the typeface.
+	NameDescription NameID = 10
+
+	// NameVendorURL is the URL of the font vendor.
+	NameVendorURL NameID = 11
+
+	// NameDesignerURL is the URL of the typeface designer.
+	NameDesignerURL NameID = 12
+
+	// NameLicense is a description of the license of the font.
+	NameLicense NameID = 13
+
+	// NameLicenseURL is the URL of the license of the font.
+	NameLicenseURL NameID = 14
+
+	// NameTypographicFamily is the typographic family name, for families with
+	// more than four styles.
+	NameTypographicFamily NameID = 16
+
+	// NameTypographicSubfamily is the typographic subfamily name, used with
+	// NameTypographicFamily.
+	NameTypographicSubfamily NameID = 17
+
+	// NameCompatibleFull is the full name of the font on the Macintosh, if it
+	// differs from NameFullName.
+	NameCompatibleFull NameID = 18
+
+	// NameSampleText is sample text showing off the font.
+	NameSampleText NameID = 19
+
+	// NamePostScriptCID is the PostScript CID findfont name.
+	NamePostScriptCID NameID = 20
+
+	// NameWWSFamily is the family name of the weight, width and slope family of
+	// the font.
+	NameWWSFamily NameID = 21
+
+	// NameWWSSubfamily is the subfamily name of the weight, width and slope
+	// family of the font.
+	NameWWSSubfamily NameID = 22
+)
+
+// NameRecord is a single record of the naming ('name') table of an SFNT font.
+type NameRecord struct {
+	// The platform, encoding and language IDs of the record, as found in the
+	// font file (e.g. 3, 1 and 0x409 for a Windows US English record).
+	PlatformID, EncodingID, LanguageID int
+
+	// The kind of string the record holds.
+	NameID NameID
+
+	// The BCP 47 language tag of the record (e.g. "en-US"), or an empty
+	// string if the language is unknown.
+	Language string
+
+	// The decoded string, or an empty string if its encoding is not supported.
+	// Unicode, Windows and ISO records are decoded from UTF-16BE (Windows
+	// symbol records from the U+F0xx range are moved to U+00xx), Macintosh
+	// Roman records from Mac OS Roman.
+	Value string
+
+	// The raw, undecoded, bytes of the string.
+	Data []byte
+}
+
+// Names returns the records of the naming ('name') table of the font. If the
+// font is not an SFNT font then ErrTableMissing is returned.
+func (f *Font) Names() ([]NameRecord, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	if f.c.face_flags&C.FT_FACE_FLAG_SFNT == 0 {
+		return nil, ErrTableMissing
+	}
+	n := int(C.FT_Get_Sfnt_Name_Count(f.c))
+	records := make([]NameRecord, 0, n)
+	for i := 0; i < n; i++ {
+		var name C.FT_SfntName
+		err := C.FT_Get_Sfnt_Name(f.c, C.FT_UInt(i), &name)
+		if err != 0 {
+			return nil, f.newError("FT_Get_Sfnt_Name", int(err))
+		}
+		rec := NameRecord{
+			PlatformID: int(name.platform_id),
+			EncodingID: int(name.encoding_id),
+			LanguageID: int(name.language_id),
+			NameID:     NameID(name.name_id),
+		}
+		if name.string_len > 0 {
+			rec.Data = C.GoBytes(unsafe.Pointer(name.string), C.int(name.string_len))
+		}
+		rec.Language = nameLanguage(rec.PlatformID, rec.LanguageID)
+		rec.Value = decodeName(rec.PlatformID, rec.EncodingID, rec.Data)
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Name returns the string of the given kind which best matches the given BCP
+// 47 language tag (e.g. "de-AT"), or false if the font has no such string.
+//
+// Records of the exact language are preferred, followed by those of a more
+// general one (e.g. "de"), those of the same primary language (e.g. "de-DE"),
+// then English ones and finally any other.
+func (f *Font) Name(id NameID, lang string) (string, bool) {
+	records, err := f.Names()
+	if err != nil {
+		return "", false
+	}
+	rec, ok := bestName(records, id, lang)
+	return rec.Value, ok
+}
+
+// bestName returns the record of the given kind which best matches the given
+// BCP 47 language tag, see Font.Name for details.
+func bestName(records []NameRecord, id NameID, lang string) (NameRecord, bool) {
+	if lang == "" {
+		lang = "en"
+	}
+	var (
+		best      NameRecord
+		bestScore = -1
+	)
+	for _, rec := range records {
+		if rec.NameID != id || rec.Value == "" {
+			continue
+		}
+		score := 4 * languageMatch(lang, rec.Language)
+		switch rec.PlatformID {
+		case 3:
+			score += 3
+		case 0:
+			score += 2
+		case 1:
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rec, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// languageMatch scores how well the language tag of a record matches the
+// wanted one, from 4 for an exact match down to 0 for unrelated languages.
+func languageMatch(want, have string) int {
+	want, have = strings.ToLower(want), strings.ToLower(have)
+	primary := func(tag string) string {
+		if i := strings.IndexByte(tag, '-'); i >= 0 {
+			return tag[:i]
+		}
+		return tag
+	}
+	switch {
+	case have == "":
+		return 0
+	case want == have:
+		return 4
+	case strings.HasPrefix(want, have+"-"):
+		return 3
+	case primary(want) == primary(have):
+		return 2
+	case primary(have) == "en":
+		return 1
+	}
+	return 0
+}
+
+// decodeName decodes the string of a name record, or returns an empty string
+// if its encoding is not supported.
+func decodeName(platformID, encodingID int, data []byte) string {
+	switch platformID {
+	case 0:
+		return decodeUTF16BE(data)
+
+	case 1:
+		if encodingID == 0 {
+			return decodeMacRoman(data)
+		}
+
+	case 2:
+		switch encodingID {
+		case 0, 2:
+			// ASCII and ISO 8859-1, which map directly onto runes.
+			r := make([]rune, len(data))
+			for i, c := range data {
+				r[i] = rune(c)
+			}
+			return string(r)
+		case 1:
+			return decodeUTF16BE(data)
+		}
+
+	case 3:
+		switch encodingID {
+		case 0:
+			// Symbol fonts may place their names in the U+F0xx range, like
+			// their characters.
+			r := []rune(decodeUTF16BE(data))
+			for i, c := range r {
+				if c >= 0xF020 && c <= 0xF0FF {
+					r[i] = c - 0xF000
+				}
+			}
+			return string(r)
+		case 1, 10:
+			return decodeUTF16BE(data)
+		}
+	}
+	return ""
+}
+
+// decodeUTF16BE decodes big-endian UTF-16 data, a trailing odd byte is
+// ignored.
+func decodeUTF16BE(data []byte) string {
+	u := make([]uint16, len(data)/2)
+	for i := range u {
+		u[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+// decodeMacRoman decodes Mac OS Roman data.
+func decodeMacRoman(data []byte) string {
+	r := make([]rune, len(data))
+	for i, c := range data {
+		if c < 0x80 {
+			r[i] = rune(c)
+		} else {
+			r[i] = macRoman[c-0x80]
+		}
+	}
+	return string(r)
+}
+
+// nameLanguage returns the BCP 47 language tag of the given platform and
+// language ID, or an empty string if it is unknown.
+func nameLanguage(platformID, languageID int) string {
+	switch platformID {
+	case 1:
+		return macLanguages[languageID]
+	case 3:
+		return windowsLanguages[languageID]
+	}
+	return ""
+}
+
+// macLanguages maps Macintosh language IDs to BCP 47 language tags.
+var macLanguages = map[int]string{
+	0: "en", 1: "fr", 2: "de", 3: "it", 4: "nl", 5: "sv", 6: "es", 7: "da",
+	8: "pt", 9: "no", 10: "he", 11: "ja", 12: "ar", 13: "fi", 14: "el",
+	15: "is", 16: "mt", 17: "tr", 18: "hr", 19: "zh-Hant", 20: "ur",
+	21: "hi", 22: "th", 23: "ko", 24: "lt", 25: "pl", 26: "hu", 27: "et",
+	28: "lv", 29: "se", 30: "fo", 31: "fa", 32: "ru", 33: "zh-Hans",
+	34: "nl-BE", 35: "ga", 36: "sq", 37: "ro", 38: "cs", 39: "sk", 40: "sl",
+	41: "yi", 42: "sr", 43: "mk", 44: "bg", 45: "uk", 46: "be", 47: "uz",
+	48: "kk", 49: "az-Cyrl", 50: "az-Arab", 51: "hy", 52: "ka", 53: "ro-MD",
+	54: "ky", 55: "tg", 56: "tk", 57: "mn-Mong", 58: "mn-Cyrl", 59: "ps",
+	60: "ku", 61: "ks", 62: "sd", 63: "bo", 64: "ne", 65: "sa", 66: "mr",
+	67: "bn", 68: "as", 69: "gu", 70: "pa", 71: "or", 72: "ml", 73: "kn",
+	74: "ta", 75: "te", 76: "si", 77: "my", 78: "km", 79: "lo", 80: "vi",
+	81: "id", 82: "tl", 83: "ms", 84: "ms-Arab", 85: "am", 86: "ti",
+	87: "om", 88: "so", 89: "sw", 90: "rw", 91: "rn", 92: "ny", 93: "mg",
+	94: "eo", 128: "cy", 129: "eu", 130: "ca", 131: "la", 132: "qu",
+	133: "gn", 134: "ay", 135: "tt", 136: "ug", 137: "dz", 138: "jv",
+	139: "su", 140: "gl", 141: "af", 142: "br", 143: "iu", 144: "gd",
+	145: "gv", 146: "ga", 147: "to", 148: "el-polyton", 149: "kl",
+	150: "az",
+}
+
+// windowsLanguages maps Windows language IDs (LCIDs) to BCP 47 language tags.
+var windowsLanguages = map[int]string{
+	0x0401: "ar-SA", 0x0402: "bg-BG", 0x0403: "ca-ES", 0x0404: "zh-TW",
+	0x0405: "cs-CZ", 0x0406: "da-DK", 0x0407: "de-DE", 0x0408: "el-GR",
+	0x0409: "en-US", 0x040A: "es-ES", 0x040B: "fi-FI", 0x040C: "fr-FR",
+	0x040D: "he-IL", 0x040E: "hu-HU", 0x040F: "is-IS", 0x0410: "it-IT",
+	0x0411: "ja-JP", 0x0412: "ko-KR", 0x0413: "nl-NL", 0x0414: "nb-NO",
+	0x0415: "pl-PL", 0x0416: "pt-BR", 0x0418: "ro-RO", 0x0419: "ru-RU",
+	0x041A: "hr-HR", 0x041B: "sk-SK", 0x041C: "sq-AL", 0x041D: "sv-SE",
+	0x041E: "th-TH", 0x041F: "tr-TR", 0x0420: "ur-PK", 0x0421: "id-ID",
+	0x0422: "uk-UA", 0x0423: "be-BY", 0x0424: "sl-SI", 0x0425: "et-EE",
+	0x0426: "lv-LV", 0x0427: "lt-LT", 0x0429: "fa-IR", 0x042A: "vi-VN",
+	0x042B: "hy-AM", 0x042D: "eu-ES", 0x042F: "mk-MK", 0x0436: "af-ZA",
+	0x0437: "ka-GE", 0x0439: "hi-IN", 0x043E: "ms-MY", 0x0441: "sw-KE",
+	0x0445: "bn-IN", 0x0449: "ta-IN", 0x044A: "te-IN", 0x0456: "gl-ES",
+	0x0804: "zh-CN", 0x0807: "de-CH", 0x0809: "en-GB", 0x080A: "es-MX",
+	0x080C: "fr-BE", 0x0810: "it-CH", 0x0813: "nl-BE", 0x0814: "nn-NO",
+	0x0816: "pt-PT", 0x081A: "sr-Latn-CS", 0x0C04: "zh-HK", 0x0C07: "de-AT",
+	0x0C09: "en-AU", 0x0C0A: "es-ES", 0x0C0C: "fr-CA", 0x0C1A: "sr-Cyrl-CS",
+	0x1004: "zh-SG", 0x1009: "en-CA", 0x100C: "fr-CH", 0x1404: "zh-MO",
+	0x1409: "en-NZ", 0x1809: "en-IE", 0x1C09: "en-ZA", 0x4009: "en-IN",
+}
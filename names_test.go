@@ -0,0 +1,135 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	records, err := font.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 22 {
+		t.Fatalf("got %d name records, want 22", len(records))
+	}
+	for _, rec := range records {
+		if rec.Value == "" {
+			t.Errorf("%+v: not decoded", rec)
+		}
+		switch rec.PlatformID {
+		case 1:
+			if rec.Language != "en" {
+				t.Errorf("Macintosh record has language %q", rec.Language)
+			}
+		case 3:
+			if rec.Language != "en-US" {
+				t.Errorf("Windows record has language %q", rec.Language)
+			}
+		}
+	}
+
+	tests := []struct {
+		id   NameID
+		lang string
+		want string
+	}{
+		{NameFamily, "en-US", "Bitstream Vera Sans"},
+		{NameFamily, "fr", "Bitstream Vera Sans"},
+		{NameSubfamily, "", "Roman"},
+		{NamePostScript, "en", "BitstreamVeraSans-Roman"},
+		{NameVendorURL, "en", "http://www.bitstream.com"},
+	}
+	for _, tst := range tests {
+		got, ok := font.Name(tst.id, tst.lang)
+		if !ok || got != tst.want {
+			t.Errorf("Name(%d, %q) = %q, %v, want %q", tst.id, tst.lang, got, ok, tst.want)
+		}
+	}
+	if c, _ := font.Name(NameCopyright, "en"); !strings.Contains(c, "Bitstream") {
+		t.Errorf("copyright %q", c)
+	}
+	if got, ok := font.Name(NameTypographicFamily, "en"); ok {
+		t.Errorf("Name(NameTypographicFamily) = %q, expected none", got)
+	}
+}
+
+func TestBestName(t *testing.T) {
+	records := []NameRecord{
+		{PlatformID: 1, Language: "en", Value: "mac en"},
+		{PlatformID: 3, Language: "en-US", Value: "win en-US"},
+		{PlatformID: 3, Language: "de-DE", Value: "win de-DE"},
+		{PlatformID: 1, Language: "de", Value: "mac de"},
+		{PlatformID: 3, Language: "de-AT", Value: "win de-AT"},
+		{PlatformID: 3, Language: "ja-JP", Value: "win ja-JP"},
+		{PlatformID: 3, NameID: NameSubfamily, Language: "fr-FR", Value: "subfamily"},
+	}
+	tests := []struct {
+		lang, want string
+	}{
+		{"de-AT", "win de-AT"},
+		{"de-CH", "mac de"},
+		{"DE-de", "win de-DE"},
+		{"de-Latn-DE", "mac de"},
+		{"en-US", "win en-US"},
+		{"en", "mac en"},
+		{"fr-CA", "win en-US"},
+		{"", "mac en"},
+		{"ja", "win ja-JP"},
+	}
+	for _, tst := range tests {
+		got, ok := bestName(records, 0, tst.lang)
+		if !ok || got.Value != tst.want {
+			t.Errorf("bestName(%q) = %q, %v, want %q", tst.lang, got.Value, ok, tst.want)
+		}
+	}
+	if got, _ := bestName(records, NameSubfamily, "en"); got.Value != "subfamily" {
+		t.Errorf("bestName(NameSubfamily) = %q, want \"subfamily\"", got.Value)
+	}
+	if _, ok := bestName(records, NameFamily, "en"); ok {
+		t.Error("bestName(NameFamily) found a record")
+	}
+}
+
+func TestDecodeName(t *testing.T) {
+	tests := []struct {
+		platformID, encodingID int
+		data                   []byte
+		want                   string
+	}{
+		{0, 3, []byte{0x00, 'A', 0xD8, 0x3D, 0xDE, 0x00}, "A\U0001F600"},
+		{1, 0, []byte{'C', 'a', 'f', 0x8E}, "Café"},
+		{1, 1, []byte{0x82, 0xA0}, ""},
+		{2, 2, []byte{'C', 'a', 'f', 0xE9}, "Café"},
+		{3, 0, []byte{0xF0, 'S', 0xF0, 'y', 0x00, 'm'}, "Sym"},
+		{3, 1, []byte{0x00, 'C', 0x00, 0xE9, 0x00}, "Cé"},
+	}
+	for _, tst := range tests {
+		if got := decodeName(tst.platformID, tst.encodingID, tst.data); got != tst.want {
+			t.Errorf("decodeName(%d, %d, % x) = %q, want %q", tst.platformID, tst.encodingID, tst.data, got, tst.want)
+		}
+	}
+}
+
+func TestNamesMissing(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.Names(); err != ErrTableMissing {
+		t.Errorf("got error %v, want %v", err, ErrTableMissing)
+	}
+	if _, ok := font.Name(NameFamily, "en"); ok {
+		t.Error("Name found a record")
+	}
+}
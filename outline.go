@@ -0,0 +1,230 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_GLYPH_H
+#include FT_OUTLINE_H
+*/
+import "C"
+
+import (
+	"image"
+	"unsafe"
+)
+
+// PointTag describes the kind of a single point in an outline.
+type PointTag uint8
+
+const (
+	// OnCurve is a point which lies on the outline.
+	OnCurve PointTag = iota
+
+	// Conic is an off-curve control point of a second-order (quadratic)
+	// Bezier arc, as used by TrueType fonts.
+	Conic
+
+	// Cubic is an off-curve control point of a third-order (cubic) Bezier
+	// arc, as used by CFF and Type 1 fonts.
+	Cubic
+)
+
+// Outline is a copy of a glyph's vector outline.
+//
+// Points are expressed in 26.6 pixel units (or font units, if the glyph was
+// loaded unscaled), and positive Y values extend upward.
+type Outline struct {
+	// The points of the outline.
+	Points []image.Point
+
+	// The tag of each point in Points.
+	Tags []PointTag
+
+	// The index into Points of the last point of each contour.
+	Contours []int
+}
+
+// SegmentOp is the operation of a single outline segment.
+type SegmentOp uint8
+
+const (
+	// MoveTo starts a new contour at Args[0].
+	MoveTo SegmentOp = iota
+
+	// LineTo draws a straight line to Args[0].
+	LineTo
+
+	// QuadTo draws a quadratic Bezier arc with the control point Args[0] to
+	// Args[1].
+	QuadTo
+
+	// CubicTo draws a cubic Bezier arc with the control points Args[0] and
+	// Args[1] to Args[2].
+	CubicTo
+)
+
+// Segment is a single drawing operation of a decomposed outline.
+type Segment struct {
+	Op   SegmentOp
+	Args [3]image.Point
+}
+
+// Decompose walks the outline and returns it as a series of segments, in the
+// same manner as FreeType's FT_Outline_Decompose. Consecutive conic control
+// points are split at their midpoint, so every contour is described purely by
+// MoveTo, LineTo, QuadTo and CubicTo operations.
+func (o *Outline) Decompose() ([]Segment, error) {
+	if len(o.Tags) != len(o.Points) {
+		return nil, ErrInvalidOutline
+	}
+
+	var (
+		segs  []Segment
+		first int
+	)
+	mid := func(a, b image.Point) image.Point {
+		return image.Pt((a.X+b.X)/2, (a.Y+b.Y)/2)
+	}
+contours:
+	for _, last := range o.Contours {
+		if last < first || last >= len(o.Points) {
+			return nil, ErrInvalidOutline
+		}
+		pts := o.Points[first : last+1]
+		tags := o.Tags[first : last+1]
+		first = last + 1
+
+		// Find the starting point of the contour.
+		start := pts[0]
+		limit := len(pts) - 1
+		i := 0
+		switch tags[0] {
+		case Cubic:
+			return nil, ErrInvalidOutline
+		case Conic:
+			if tags[limit] == OnCurve {
+				// Start at the last point if it is on the curve.
+				start = pts[limit]
+				limit--
+			} else {
+				// Both first and last points are conic; start at their middle.
+				start = mid(pts[0], pts[limit])
+			}
+			i = -1
+		}
+		segs = append(segs, Segment{Op: MoveTo, Args: [3]image.Point{start}})
+
+		for i < limit {
+			i++
+			switch tags[i] {
+			case OnCurve:
+				segs = append(segs, Segment{Op: LineTo, Args: [3]image.Point{pts[i]}})
+
+			case Conic:
+				ctrl := pts[i]
+				for {
+					if i >= limit {
+						segs = append(segs, Segment{Op: QuadTo, Args: [3]image.Point{ctrl, start}})
+						continue contours
+					}
+					i++
+					p := pts[i]
+					if tags[i] == OnCurve {
+						segs = append(segs, Segment{Op: QuadTo, Args: [3]image.Point{ctrl, p}})
+						break
+					}
+					if tags[i] != Conic {
+						return nil, ErrInvalidOutline
+					}
+					segs = append(segs, Segment{Op: QuadTo, Args: [3]image.Point{ctrl, mid(ctrl, p)}})
+					ctrl = p
+				}
+
+			case Cubic:
+				if i+1 > limit || tags[i+1] != Cubic {
+					return nil, ErrInvalidOutline
+				}
+				c1, c2 := pts[i], pts[i+1]
+				i += 2
+				if i <= limit {
+					segs = append(segs, Segment{Op: CubicTo, Args: [3]image.Point{c1, c2, pts[i]}})
+					continue
+				}
+				segs = append(segs, Segment{Op: CubicTo, Args: [3]image.Point{c1, c2, start}})
+				continue contours
+			}
+		}
+
+		// Close the contour with a line back to the start.
+		segs = append(segs, Segment{Op: LineTo, Args: [3]image.Point{start}})
+	}
+	if first != len(o.Points) {
+		return nil, ErrInvalidOutline
+	}
+	return segs, nil
+}
+
+// Outline returns a copy of the glyph's vector outline.
+//
+// If the glyph is not an outline (e.g. it comes from a bitmap-only font) then
+// ErrInvalidGlyphFormat is returned.
+func (g *Glyph) Outline() (*Outline, error) {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	if g.c != nil {
+		if g.c.format != C.FT_GLYPH_FORMAT_OUTLINE {
+			return nil, ErrInvalidGlyphFormat
+		}
+		return newOutline(&C.FT_OutlineGlyph(unsafe.Pointer(g.c)).outline), nil
+	}
+
+	slot, err := g.slot()
+	if err != nil {
+		return nil, err
+	}
+	if slot.format != C.FT_GLYPH_FORMAT_OUTLINE {
+		return nil, ErrInvalidGlyphFormat
+	}
+	return newOutline(&slot.outline), nil
+}
+
+// newOutline copies the given FreeType outline into a Go-owned one.
+func newOutline(o *C.FT_Outline) *Outline {
+	nPoints := int(o.n_points)
+	nContours := int(o.n_contours)
+	out := &Outline{
+		Points:   make([]image.Point, nPoints),
+		Tags:     make([]PointTag, nPoints),
+		Contours: make([]int, nContours),
+	}
+	if nPoints > 0 {
+		points := unsafe.Slice(o.points, nPoints)
+		tags := unsafe.Slice((*uint8)(unsafe.Pointer(o.tags)), nPoints)
+		for i, p := range points {
+			out.Points[i] = image.Pt(int(p.x), int(p.y))
+			switch tags[i] & 3 {
+			case 0:
+				out.Tags[i] = Conic
+			case 2:
+				out.Tags[i] = Cubic
+			default:
+				out.Tags[i] = OnCurve
+			}
+		}
+	}
+	if nContours > 0 {
+		for i, c := range unsafe.Slice(o.contours, nContours) {
+			out.Contours[i] = int(c)
+		}
+	}
+	return out
+}
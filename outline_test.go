@@ -0,0 +1,162 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestGlyphOutline(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	glyph, err := font.Load(font.Index('O'))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rendering the glyph must not prevent access to the outline afterwards.
+	if _, err := glyph.Image(); err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := glyph.Outline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outline.Contours) != 2 {
+		t.Fatalf("'O' has %d contours, want 2", len(outline.Contours))
+	}
+	if len(outline.Points) != len(outline.Tags) {
+		t.Fatal("number of points and tags differ")
+	}
+
+	segs, err := outline.Decompose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := 0
+	for _, s := range segs {
+		switch s.Op {
+		case MoveTo:
+			moves++
+		case CubicTo:
+			t.Fatal("TrueType outline decomposed into a cubic segment")
+		}
+	}
+	if moves != len(outline.Contours) {
+		t.Fatalf("got %d MoveTo segments, want %d", moves, len(outline.Contours))
+	}
+}
+
+func TestOutlineDecompose(t *testing.T) {
+	pt := image.Pt
+	tests := []struct {
+		name    string
+		outline Outline
+		want    []Segment
+	}{
+		{
+			name: "lines",
+			outline: Outline{
+				Points:   []image.Point{pt(0, 0), pt(10, 0), pt(10, 10)},
+				Tags:     []PointTag{OnCurve, OnCurve, OnCurve},
+				Contours: []int{2},
+			},
+			want: []Segment{
+				{Op: MoveTo, Args: [3]image.Point{pt(0, 0)}},
+				{Op: LineTo, Args: [3]image.Point{pt(10, 0)}},
+				{Op: LineTo, Args: [3]image.Point{pt(10, 10)}},
+				{Op: LineTo, Args: [3]image.Point{pt(0, 0)}},
+			},
+		},
+		{
+			name: "implied on-curve points",
+			outline: Outline{
+				Points:   []image.Point{pt(0, 0), pt(10, 0), pt(10, 10), pt(0, 10)},
+				Tags:     []PointTag{Conic, Conic, Conic, Conic},
+				Contours: []int{3},
+			},
+			want: []Segment{
+				{Op: MoveTo, Args: [3]image.Point{pt(0, 5)}},
+				{Op: QuadTo, Args: [3]image.Point{pt(0, 0), pt(5, 0)}},
+				{Op: QuadTo, Args: [3]image.Point{pt(10, 0), pt(10, 5)}},
+				{Op: QuadTo, Args: [3]image.Point{pt(10, 10), pt(5, 10)}},
+				{Op: QuadTo, Args: [3]image.Point{pt(0, 10), pt(0, 5)}},
+			},
+		},
+		{
+			name: "conic start",
+			outline: Outline{
+				Points:   []image.Point{pt(10, 0), pt(10, 10), pt(0, 0)},
+				Tags:     []PointTag{Conic, OnCurve, OnCurve},
+				Contours: []int{2},
+			},
+			want: []Segment{
+				{Op: MoveTo, Args: [3]image.Point{pt(0, 0)}},
+				{Op: QuadTo, Args: [3]image.Point{pt(10, 0), pt(10, 10)}},
+				{Op: LineTo, Args: [3]image.Point{pt(0, 0)}},
+			},
+		},
+		{
+			name: "cubic",
+			outline: Outline{
+				Points: []image.Point{
+					pt(0, 0), pt(0, 10), pt(10, 10), pt(10, 0),
+					pt(20, 0), pt(20, 10), pt(30, 10), pt(30, 0),
+				},
+				Tags: []PointTag{
+					OnCurve, Cubic, Cubic, OnCurve,
+					OnCurve, Cubic, Cubic, OnCurve,
+				},
+				Contours: []int{3, 7},
+			},
+			want: []Segment{
+				{Op: MoveTo, Args: [3]image.Point{pt(0, 0)}},
+				{Op: CubicTo, Args: [3]image.Point{pt(0, 10), pt(10, 10), pt(10, 0)}},
+				{Op: LineTo, Args: [3]image.Point{pt(0, 0)}},
+				{Op: MoveTo, Args: [3]image.Point{pt(20, 0)}},
+				{Op: CubicTo, Args: [3]image.Point{pt(20, 10), pt(30, 10), pt(30, 0)}},
+				{Op: LineTo, Args: [3]image.Point{pt(20, 0)}},
+			},
+		},
+	}
+	for _, tst := range tests {
+		got, err := tst.outline.Decompose()
+		if err != nil {
+			t.Errorf("%s: %v", tst.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tst.want) {
+			t.Errorf("%s:\ngot  %v\nwant %v", tst.name, got, tst.want)
+		}
+	}
+}
+
+func TestOutlineDecomposeInvalid(t *testing.T) {
+	bad := []Outline{
+		{
+			Points:   []image.Point{{}, {}},
+			Tags:     []PointTag{OnCurve},
+			Contours: []int{1},
+		},
+		{
+			Points:   []image.Point{{}, {}, {}},
+			Tags:     []PointTag{OnCurve, Cubic, OnCurve},
+			Contours: []int{2},
+		},
+		{
+			Points:   []image.Point{{}, {}},
+			Tags:     []PointTag{OnCurve, OnCurve},
+			Contours: []int{5},
+		},
+	}
+	for i, o := range bad {
+		if _, err := o.Decompose(); err != ErrInvalidOutline {
+			t.Errorf("outline %d: got error %v, want ErrInvalidOutline", i, err)
+		}
+	}
+}
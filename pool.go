@@ -0,0 +1,90 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import "sync"
+
+// ContextPool is a pool of contexts, which lets several goroutines each use a
+// context of their own. A typical worker gets a context, clones the fonts it
+// needs into it and puts the context back once it is done:
+//
+//	ctx, err := pool.Get()
+//	if err != nil {
+//		return err
+//	}
+//	defer pool.Put(ctx)
+//	font, err := sharedFont.Clone(ctx)
+//
+// Contexts remain open while in the pool, along with the fonts loaded into
+// them, until the pool is closed.
+//
+// Its zero value is an empty pool ready to use. All methods are safe for
+// concurrent use.
+type ContextPool struct {
+	access sync.Mutex
+
+	// The contexts which are not in use, and all contexts of the pool.
+	free, all []*Context
+
+	// Whether the pool has been closed.
+	closed bool
+}
+
+// Get returns a context of the pool which is not in use, creating a new one if
+// there is none. If the pool has been closed then ErrClosed is returned.
+func (p *ContextPool) Get() (*Context, error) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+	if n := len(p.free); n > 0 {
+		c := p.free[n-1]
+		p.free = p.free[:n-1]
+		return c, nil
+	}
+	c, err := Init()
+	if err != nil {
+		return nil, err
+	}
+	p.all = append(p.all, c)
+	return c, nil
+}
+
+// Put returns a context obtained from Get to the pool, after which it must not
+// be used any more. If the pool has been closed then the context is closed
+// instead.
+func (p *ContextPool) Put(c *Context) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.closed {
+		c.Close()
+		return
+	}
+	p.free = append(p.free, c)
+}
+
+// Close closes all contexts of the pool, including those which are in use,
+// along with their fonts. It is safe to call Close more than once.
+func (p *ContextPool) Close() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	var firstErr error
+	for _, c := range p.all {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	p.free, p.all = nil, nil
+	return firstErr
+}
@@ -0,0 +1,198 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_GLYPH_H
+#include FT_LCD_FILTER_H
+*/
+import "C"
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"unsafe"
+)
+
+// RenderMode selects how a glyph is rendered into a bitmap, they map onto
+// FreeType's FT_RENDER_MODE_* modes.
+type RenderMode int
+
+const (
+	// RenderNormal renders 8-bit anti-aliased coverage, as an *image.Alpha.
+	RenderNormal RenderMode = C.FT_RENDER_MODE_NORMAL
+
+	// RenderLight is identical to RenderNormal, it exists to pair with the
+	// TargetLight hinting mode.
+	RenderLight RenderMode = C.FT_RENDER_MODE_LIGHT
+
+	// RenderMono renders 1-bit coverage, as an *image.Paletted whose palette
+	// is MonoPalette.
+	RenderMono RenderMode = C.FT_RENDER_MODE_MONO
+
+	// RenderLCD renders coverage for horizontally decimated (RGB) LCD
+	// displays, as an *image.NRGBA whose red, green and blue channels hold the
+	// coverage of each subpixel and whose alpha channel is always opaque.
+	RenderLCD RenderMode = C.FT_RENDER_MODE_LCD
+
+	// RenderLCDV renders coverage for vertically decimated (RGB) LCD
+	// displays, in the same format as RenderLCD.
+	RenderLCDV RenderMode = C.FT_RENDER_MODE_LCD_V
+)
+
+// MonoPalette is the palette of images rendered using RenderMono, index zero
+// is fully transparent and index one is fully opaque.
+var MonoPalette = color.Palette{color.Alpha{0x00}, color.Alpha{0xff}}
+
+// LcdFilter is a filter that is applied to glyphs rendered in the LCD render
+// modes to reduce color fringes, they map onto FreeType's FT_LCD_FILTER_*
+// filters.
+type LcdFilter int
+
+const (
+	// LcdFilterNone applies no filter.
+	LcdFilterNone LcdFilter = C.FT_LCD_FILTER_NONE
+
+	// LcdFilterDefault is FreeType's recommended filter.
+	LcdFilterDefault LcdFilter = C.FT_LCD_FILTER_DEFAULT
+
+	// LcdFilterLight is a sharper, but more colorful, filter.
+	LcdFilterLight LcdFilter = C.FT_LCD_FILTER_LIGHT
+
+	// LcdFilterLegacy is the filter used by older versions of libXft.
+	LcdFilterLegacy LcdFilter = C.FT_LCD_FILTER_LEGACY
+)
+
+// SetLcdFilter sets the filter that is applied to glyphs from fonts of this
+// context when they are rendered using RenderLCD or RenderLCDV.
+//
+// If FreeType was built without subpixel rendering support then
+// ErrUnimplementedFeature is returned.
+func (c *Context) SetLcdFilter(filter LcdFilter) error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	err := C.FT_Library_SetLcdFilter(c.c, C.FT_LcdFilter(filter))
+	if err != 0 {
+		return newError("FT_Library_SetLcdFilter", int(err))
+	}
+	c.lcdFilter = filter
+	return nil
+}
+
+// Render renders the glyph using the given render mode and returns a copy of
+// the resulting image.
+//
+// The type of the returned image depends on the render mode (see the
+// RenderMode constants), or on the glyph itself if it is an embedded bitmap.
+// Like GlyphImage, its bounds are relative to the glyph origin.
+//
+// If the render mode is RenderLCD or RenderLCDV, an LCD filter other than
+// LcdFilterNone was set and FreeType was built without subpixel rendering
+// support then ErrUnimplementedFeature is returned.
+func (g *Glyph) Render(mode RenderMode) (image.Image, error) {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	return g.render(mode)
+}
+
+// RenderInto renders the glyph using RenderNormal directly into dst, placing
+// the glyph's origin (i.e. the pen position on the baseline) at the given
+// point. The glyph is composited over the existing contents of dst and clipped
+// to its bounds.
+func (g *Glyph) RenderInto(dst *image.Alpha, at image.Point) error {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return err
+	}
+
+	bm, done, err := g.bitmap(RenderNormal)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if bm.mode == pixelModeGray && bm.numGrays == 256 {
+		return bm.drawOver(dst, at)
+	}
+	img, err := bm.image()
+	if err != nil {
+		return err
+	}
+	b := img.Bounds()
+	draw.Draw(dst, b.Add(at), img, b.Min, draw.Over)
+	return nil
+}
+
+// render implements Render, the font lock must be held.
+func (g *Glyph) render(mode RenderMode) (image.Image, error) {
+	bm, done, err := g.bitmap(mode)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return bm.image()
+}
+
+// bitmap renders the glyph using the given render mode and returns a view of
+// the resulting bitmap, which remains valid until done is called. The font
+// lock must be held.
+func (g *Glyph) bitmap(mode RenderMode) (bm *bitmap, done func(), err error) {
+	c := g.font.ctx
+	if (mode == RenderLCD || mode == RenderLCDV) && c.lcdFilter != LcdFilterNone {
+		// The filter belongs to the library, not the face. Builds without
+		// subpixel rendering support render LCD bitmaps only unfiltered.
+		c.lcdAccess.Lock()
+		defer c.lcdAccess.Unlock()
+
+		ftErr := C.FT_Library_SetLcdFilter(c.c, C.FT_LcdFilter(c.lcdFilter))
+		if ftErr != 0 {
+			return nil, nil, newError("FT_Library_SetLcdFilter", int(ftErr))
+		}
+	}
+
+	if g.c != nil {
+		// Persistent glyph, render a copy so that it stays intact.
+		var tmp C.FT_Glyph
+		ftErr := C.FT_Glyph_Copy(g.c, &tmp)
+		if ftErr != 0 {
+			return nil, nil, newGlyphError("FT_Glyph_Copy", int(ftErr), g.index)
+		}
+		ftErr = C.FT_Glyph_To_Bitmap(&tmp, C.FT_Render_Mode(mode), nil, 1)
+		if ftErr != 0 {
+			C.FT_Done_Glyph(tmp)
+			return nil, nil, newGlyphError("FT_Glyph_To_Bitmap", int(ftErr), g.index)
+		}
+		bg := C.FT_BitmapGlyph(unsafe.Pointer(tmp))
+		return newBitmap(&bg.bitmap, bg.left, bg.top), func() { C.FT_Done_Glyph(tmp) }, nil
+	}
+
+	slot, err := g.slot()
+	if err != nil {
+		return nil, nil, err
+	}
+	ftErr := C.FT_Render_Glyph(slot, C.FT_Render_Mode(mode))
+	if ftErr != 0 {
+		return nil, nil, newGlyphError("FT_Render_Glyph", int(ftErr), g.index)
+	}
+
+	// The slot now holds a bitmap instead of the loaded glyph.
+	g.font.slot = nil
+
+	return newBitmap(&slot.bitmap, slot.bitmap_left, slot.bitmap_top), func() {}, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+)
+
+func TestGlyphRender(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 32); err != nil {
+		t.Fatal(err)
+	}
+	glyph, err := font.Load(font.Index('W'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	normal, err := glyph.Render(RenderNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := normal.(*image.Alpha); !ok {
+		t.Fatalf("RenderNormal: got %T, want *image.Alpha", normal)
+	}
+
+	mono, err := glyph.Render(RenderMono)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := mono.(*image.Paletted)
+	if !ok {
+		t.Fatalf("RenderMono: got %T, want *image.Paletted", mono)
+	}
+	set := 0
+	for _, v := range p.Pix {
+		if v > 1 {
+			t.Fatalf("RenderMono: palette index %d out of range", v)
+		}
+		set += int(v)
+	}
+	if set == 0 {
+		t.Fatal("RenderMono: no pixels set")
+	}
+}
+
+func TestGlyphRenderLCD(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 32); err != nil {
+		t.Fatal(err)
+	}
+	glyph, err := font.Load(font.Index('W'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	normal, err := glyph.Render(RenderNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := normal.Bounds().Size()
+
+	// Without a filter LCD rendering needs no subpixel rendering support.
+	if _, err := glyph.Render(RenderLCD); err != nil {
+		t.Fatalf("RenderLCD without filter: %v", err)
+	}
+
+	if err := font.ctx.SetLcdFilter(LcdFilterDefault); errors.Is(err, ErrUnimplementedFeature) {
+		t.Skip("FreeType built without subpixel rendering support")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	lcd, err := glyph.Render(RenderLCD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lcd.(*image.NRGBA); !ok {
+		t.Fatalf("RenderLCD: got %T, want *image.NRGBA", lcd)
+	}
+	if got := lcd.Bounds().Dy(); got != size.Y {
+		t.Errorf("RenderLCD: height %d, want %d", got, size.Y)
+	}
+
+	lcdv, err := glyph.Render(RenderLCDV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lcdv.(*image.NRGBA); !ok {
+		t.Fatalf("RenderLCDV: got %T, want *image.NRGBA", lcdv)
+	}
+	if got := lcdv.Bounds().Dx(); got != size.X {
+		t.Errorf("RenderLCDV: width %d, want %d", got, size.X)
+	}
+}
+
+func TestGlyphCopy(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	var (
+		glyphs []*Glyph
+		want   [][]byte
+	)
+	for _, r := range "ABC" {
+		glyph, err := font.Load(font.Index(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := glyph.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cpy, err := glyph.Copy()
+		if err != nil {
+			t.Fatal(err)
+		}
+		glyphs = append(glyphs, cpy)
+		want = append(want, img.Pix)
+	}
+
+	// Persistent glyphs must stay intact while others are loaded and rendered,
+	// even concurrently.
+	errs := make(chan error, len(glyphs))
+	for i, g := range glyphs {
+		go func(i int, g *Glyph) {
+			img, err := g.Image()
+			if err == nil && !bytes.Equal(img.Pix, want[i]) {
+				err = fmt.Errorf("glyph %d: image of persistent copy differs", i)
+			}
+			errs <- err
+		}(i, g)
+	}
+	if _, err := font.Load(font.Index('Z')); err != nil {
+		t.Fatal(err)
+	}
+	for range glyphs {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := glyphs[0].Outline(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlyphRenderInto(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	glyph, err := font.Load(font.Index('g'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := glyph.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := image.Pt(3, 32)
+	dst := image.NewAlpha(image.Rect(0, 0, 64, 64))
+	if err := glyph.RenderInto(dst, at); err != nil {
+		t.Fatal(err)
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := image.Pt(x, y).Add(at)
+			if got, want := dst.AlphaAt(p.X, p.Y), img.AlphaAt(x, y); got != want {
+				t.Fatalf("pixel %v: got %v, want %v", p, got, want)
+			}
+		}
+	}
+
+	// Rendering partially outside of dst is clipped.
+	small := image.NewAlpha(image.Rect(0, 0, 4, 4))
+	if err := glyph.RenderInto(small, image.Pt(-2, -2)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlyphImagePlacement(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	for _, r := range "Ag_" {
+		glyph, err := font.Load(font.Index(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := glyph.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Vera is hinted at the default size, so the bearings are integral.
+		want := image.Pt(glyph.HMetrics.BearingX>>6, -glyph.HMetrics.BearingY>>6)
+		if got := img.Bounds().Min; got != want {
+			t.Errorf("%q: image placed at %v, want %v", r, got, want)
+		}
+	}
+}
@@ -0,0 +1,599 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_TRUETYPE_TABLES_H
+*/
+import "C"
+
+import (
+	"image"
+	"time"
+	"unsafe"
+)
+
+// HeadTable is the font header ('head') table of an SFNT font. Unless noted
+// otherwise, values are expressed in font units.
+type HeadTable struct {
+	// The version of the table, and the revision of the font as set by its
+	// manufacturer.
+	Version, FontRevision float64
+
+	// The checksum adjustment and magic number (0x5F0F3CF5) of the font.
+	CheckSumAdjustment, MagicNumber uint32
+
+	// The flags of the font, e.g. bit 0 is set if the baseline is at y=0.
+	Flags uint16
+
+	// The number of font units per EM square.
+	UnitsPerEm int
+
+	// The creation and modification dates of the font.
+	Created, Modified time.Time
+
+	// The bounding box of all glyphs in the font.
+	BBox image.Rectangle
+
+	// The style of the font, bit 0 is set for bold fonts and bit 1 for italic
+	// ones.
+	MacStyle uint16
+
+	// The smallest readable size of the font, in pixels per EM.
+	LowestRecPPEM int
+
+	// The deprecated font direction hint.
+	FontDirectionHint int
+
+	// The format of the 'loca' table (0 for short offsets, 1 for long ones)
+	// and of the glyph data.
+	IndexToLocFormat, GlyphDataFormat int
+}
+
+// HeadTable returns the font header ('head') table of the font. If the font is
+// not an SFNT font then ErrTableMissing is returned.
+func (f *Font) HeadTable() (*HeadTable, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_head)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_Header)(p)
+	return &HeadTable{
+		Version:            fixedToFloat(t.Table_Version),
+		FontRevision:       fixedToFloat(t.Font_Revision),
+		CheckSumAdjustment: uint32(t.CheckSum_Adjust),
+		MagicNumber:        uint32(t.Magic_Number),
+		Flags:              uint16(t.Flags),
+		UnitsPerEm:         int(t.Units_Per_EM),
+		Created:            longDateTime(uint32(t.Created[0]), uint32(t.Created[1])),
+		Modified:           longDateTime(uint32(t.Modified[0]), uint32(t.Modified[1])),
+		BBox:               image.Rect(int(t.xMin), int(t.yMin), int(t.xMax), int(t.yMax)),
+		MacStyle:           uint16(t.Mac_Style),
+		LowestRecPPEM:      int(t.Lowest_Rec_PPEM),
+		FontDirectionHint:  int(t.Font_Direction),
+		IndexToLocFormat:   int(t.Index_To_Loc_Format),
+		GlyphDataFormat:    int(t.Glyph_Data_Format),
+	}, nil
+}
+
+// MetricsHeader is a horizontal ('hhea') or vertical ('vhea') metrics header
+// table of an SFNT font. Unless noted otherwise, values are expressed in font
+// units.
+//
+// For vertical headers, the fields named after horizontal metrics hold their
+// vertical counterpart: e.g. AdvanceMax is the maximum advance height and
+// MinLeftSideBearing the minimum top side bearing.
+type MetricsHeader struct {
+	// The version of the table.
+	Version float64
+
+	// The typographic ascender, descender and line gap of the font.
+	Ascender, Descender, LineGap int
+
+	// The maximum advance of all glyphs.
+	AdvanceMax int
+
+	// The minimum side bearings, and the maximum extent, of all glyphs.
+	MinLeftSideBearing, MinRightSideBearing, MaxExtent int
+
+	// The slope of the caret (Rise/Run), and its offset for slanted fonts.
+	CaretSlopeRise, CaretSlopeRun, CaretOffset int
+
+	// The format of the metrics data, and the number of long metrics entries.
+	MetricDataFormat, NumberOfMetrics int
+}
+
+// HheaTable returns the horizontal header ('hhea') table of the font. If the
+// font is not an SFNT font then ErrTableMissing is returned.
+func (f *Font) HheaTable() (*MetricsHeader, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_hhea)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_HoriHeader)(p)
+	return &MetricsHeader{
+		Version:             versionToFloat(t.Version),
+		Ascender:            int(t.Ascender),
+		Descender:           int(t.Descender),
+		LineGap:             int(t.Line_Gap),
+		AdvanceMax:          int(t.advance_Width_Max),
+		MinLeftSideBearing:  int(t.min_Left_Side_Bearing),
+		MinRightSideBearing: int(t.min_Right_Side_Bearing),
+		MaxExtent:           int(t.xMax_Extent),
+		CaretSlopeRise:      int(t.caret_Slope_Rise),
+		CaretSlopeRun:       int(t.caret_Slope_Run),
+		CaretOffset:         int(t.caret_Offset),
+		MetricDataFormat:    int(t.metric_Data_Format),
+		NumberOfMetrics:     int(t.number_Of_HMetrics),
+	}, nil
+}
+
+// VheaTable returns the vertical header ('vhea') table of the font. If the
+// font is not an SFNT font, or has no vertical metrics, then ErrTableMissing
+// is returned.
+func (f *Font) VheaTable() (*MetricsHeader, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_vhea)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_VertHeader)(p)
+	return &MetricsHeader{
+		Version:             versionToFloat(t.Version),
+		Ascender:            int(t.Ascender),
+		Descender:           int(t.Descender),
+		LineGap:             int(t.Line_Gap),
+		AdvanceMax:          int(t.advance_Height_Max),
+		MinLeftSideBearing:  int(t.min_Top_Side_Bearing),
+		MinRightSideBearing: int(t.min_Bottom_Side_Bearing),
+		MaxExtent:           int(t.yMax_Extent),
+		CaretSlopeRise:      int(t.caret_Slope_Rise),
+		CaretSlopeRun:       int(t.caret_Slope_Run),
+		CaretOffset:         int(t.caret_Offset),
+		MetricDataFormat:    int(t.metric_Data_Format),
+		NumberOfMetrics:     int(t.number_Of_VMetrics),
+	}, nil
+}
+
+// EmbeddingFlags are the embedding permissions of a font, as found in the
+// fsType field of its OS/2 table. A value of zero means installable
+// embedding.
+type EmbeddingFlags uint16
+
+const (
+	// EmbedRestricted is set if the font must not be embedded.
+	EmbedRestricted EmbeddingFlags = 0x0002
+
+	// EmbedPreviewPrint is set if the font may only be embedded for previewing
+	// and printing documents.
+	EmbedPreviewPrint EmbeddingFlags = 0x0004
+
+	// EmbedEditable is set if the font may be embedded in documents which are
+	// editable.
+	EmbedEditable EmbeddingFlags = 0x0008
+
+	// EmbedNoSubsetting is set if the font must be embedded in full.
+	EmbedNoSubsetting EmbeddingFlags = 0x0100
+
+	// EmbedBitmapOnly is set if only the embedded bitmaps of the font may be
+	// embedded.
+	EmbedBitmapOnly EmbeddingFlags = 0x0200
+)
+
+// SelectionFlags describe the style of a font, as found in the fsSelection
+// field of its OS/2 table.
+type SelectionFlags uint16
+
+const (
+	// SelectionItalic marks italic fonts.
+	SelectionItalic SelectionFlags = 1 << 0
+
+	// SelectionUnderscore marks fonts whose glyphs are underscored.
+	SelectionUnderscore SelectionFlags = 1 << 1
+
+	// SelectionNegative marks fonts whose glyphs have their foreground and
+	// background reversed.
+	SelectionNegative SelectionFlags = 1 << 2
+
+	// SelectionOutlined marks fonts whose glyphs are outlined (hollow).
+	SelectionOutlined SelectionFlags = 1 << 3
+
+	// SelectionStrikeout marks fonts whose glyphs are struck out.
+	SelectionStrikeout SelectionFlags = 1 << 4
+
+	// SelectionBold marks bold fonts.
+	SelectionBold SelectionFlags = 1 << 5
+
+	// SelectionRegular marks regular fonts, neither bold nor italic.
+	SelectionRegular SelectionFlags = 1 << 6
+
+	// SelectionUseTypoMetrics tells that the typographic ascender, descender and
+	// line gap of the OS/2 table should be used for line spacing.
+	SelectionUseTypoMetrics SelectionFlags = 1 << 7
+
+	// SelectionWWS tells that the font's family names follow the weight, width
+	// and slope model.
+	SelectionWWS SelectionFlags = 1 << 8
+
+	// SelectionOblique marks oblique fonts, as opposed to italic ones.
+	SelectionOblique SelectionFlags = 1 << 9
+)
+
+// OS2Table is the OS/2 and Windows metrics ('OS/2') table of an SFNT font.
+// Unless noted otherwise, values are expressed in font units.
+type OS2Table struct {
+	// The version of the table. Fields introduced by later versions are zero
+	// for earlier ones.
+	Version int
+
+	// The average advance width of the glyphs.
+	AvgCharWidth int
+
+	// The weight (e.g. 400 for normal, 700 for bold) and width (e.g. 5 for
+	// normal, 1 for ultra-condensed) classes of the font.
+	WeightClass, WidthClass int
+
+	// The embedding permissions of the font.
+	Type EmbeddingFlags
+
+	// The size and offset of subscript and superscript glyphs.
+	SubscriptSize, SubscriptOffset     image.Point
+	SuperscriptSize, SuperscriptOffset image.Point
+
+	// The thickness and position (above the baseline) of the strikeout
+	// stroke.
+	StrikeoutSize, StrikeoutPosition int
+
+	// The IBM font family class and subclass of the font.
+	FamilyClass int
+
+	// The PANOSE classification of the font.
+	Panose [10]byte
+
+	// The Unicode ranges the font covers, as a 128-bit set with bit 0 being
+	// the least significant bit of UnicodeRange[0].
+	UnicodeRange [4]uint32
+
+	// The four character identifier of the font vendor (e.g. "Bits").
+	VendorID string
+
+	// The style of the font.
+	Selection SelectionFlags
+
+	// The smallest and largest BMP character codes mapped by the font.
+	FirstCharIndex, LastCharIndex rune
+
+	// The typographic ascender, descender and line gap of the font.
+	TypoAscender, TypoDescender, TypoLineGap int
+
+	// The Windows clipping ascent and descent of the font, both positive.
+	WinAscent, WinDescent int
+
+	// The code pages the font covers, as a 64-bit set (version 1 and later).
+	CodePageRange [2]uint32
+
+	// The height of lowercase and uppercase letters (version 2 and later).
+	XHeight, CapHeight int
+
+	// The characters used for missing characters and word breaks, and the
+	// maximum context length of the font's OpenType features (version 2 and
+	// later).
+	DefaultChar, BreakChar rune
+	MaxContext             int
+}
+
+// OS2Table returns the OS/2 and Windows metrics ('OS/2') table of the font. If
+// the font is not an SFNT font, or has no such table, then ErrTableMissing is
+// returned.
+func (f *Font) OS2Table() (*OS2Table, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_os2)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_OS2)(p)
+	if t.version == 0xFFFF {
+		return nil, ErrTableMissing
+	}
+	os2 := &OS2Table{
+		Version:           int(t.version),
+		AvgCharWidth:      int(t.xAvgCharWidth),
+		WeightClass:       int(t.usWeightClass),
+		WidthClass:        int(t.usWidthClass),
+		Type:              EmbeddingFlags(t.fsType),
+		SubscriptSize:     image.Pt(int(t.ySubscriptXSize), int(t.ySubscriptYSize)),
+		SubscriptOffset:   image.Pt(int(t.ySubscriptXOffset), int(t.ySubscriptYOffset)),
+		SuperscriptSize:   image.Pt(int(t.ySuperscriptXSize), int(t.ySuperscriptYSize)),
+		SuperscriptOffset: image.Pt(int(t.ySuperscriptXOffset), int(t.ySuperscriptYOffset)),
+		StrikeoutSize:     int(t.yStrikeoutSize),
+		StrikeoutPosition: int(t.yStrikeoutPosition),
+		FamilyClass:       int(t.sFamilyClass),
+		UnicodeRange: [4]uint32{
+			uint32(t.ulUnicodeRange1),
+			uint32(t.ulUnicodeRange2),
+			uint32(t.ulUnicodeRange3),
+			uint32(t.ulUnicodeRange4),
+		},
+		VendorID:       tableString(t.achVendID[:]),
+		Selection:      SelectionFlags(t.fsSelection),
+		FirstCharIndex: rune(t.usFirstCharIndex),
+		LastCharIndex:  rune(t.usLastCharIndex),
+		TypoAscender:   int(t.sTypoAscender),
+		TypoDescender:  int(t.sTypoDescender),
+		TypoLineGap:    int(t.sTypoLineGap),
+		WinAscent:      int(t.usWinAscent),
+		WinDescent:     int(t.usWinDescent),
+		CodePageRange: [2]uint32{
+			uint32(t.ulCodePageRange1),
+			uint32(t.ulCodePageRange2),
+		},
+		XHeight:     int(t.sxHeight),
+		CapHeight:   int(t.sCapHeight),
+		DefaultChar: rune(t.usDefaultChar),
+		BreakChar:   rune(t.usBreakChar),
+		MaxContext:  int(t.usMaxContext),
+	}
+	for i, b := range t.panose {
+		os2.Panose[i] = byte(b)
+	}
+	return os2, nil
+}
+
+// PostTable is the PostScript ('post') table of an SFNT font. Unless noted
+// otherwise, values are expressed in font units.
+type PostTable struct {
+	// The format of the table (e.g. 2 if it holds glyph names, 3 if not).
+	Format float64
+
+	// The italic angle of the font in degrees, counter-clockwise from the
+	// vertical (i.e. negative for fonts leaning to the right).
+	ItalicAngle float64
+
+	// The position (above the baseline, so usually negative) and thickness of
+	// the underline.
+	UnderlinePosition, UnderlineThickness int
+
+	// Whether the font is monospaced.
+	IsFixedPitch bool
+
+	// Memory usage hints for downloading the font as a Type 42 or Type 1
+	// font.
+	MinMemType42, MaxMemType42, MinMemType1, MaxMemType1 uint32
+}
+
+// PostTable returns the PostScript ('post') table of the font. If the font is
+// not an SFNT font then ErrTableMissing is returned.
+func (f *Font) PostTable() (*PostTable, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_post)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_Postscript)(p)
+	return &PostTable{
+		Format:             versionToFloat(t.FormatType),
+		ItalicAngle:        fixedToFloat(t.italicAngle),
+		UnderlinePosition:  int(t.underlinePosition),
+		UnderlineThickness: int(t.underlineThickness),
+		IsFixedPitch:       t.isFixedPitch != 0,
+		MinMemType42:       uint32(t.minMemType42),
+		MaxMemType42:       uint32(t.maxMemType42),
+		MinMemType1:        uint32(t.minMemType1),
+		MaxMemType1:        uint32(t.maxMemType1),
+	}, nil
+}
+
+// MaxpTable is the maximum profile ('maxp') table of an SFNT font. Version
+// 0.5 tables, used by CFF fonts, only hold the number of glyphs.
+type MaxpTable struct {
+	// The version of the table.
+	Version float64
+
+	// The number of glyphs in the font.
+	NumGlyphs int
+
+	// The maximum number of points and contours of simple and composite
+	// glyphs.
+	MaxPoints, MaxContours                   int
+	MaxCompositePoints, MaxCompositeContours int
+
+	// The number of zones, and the number of points in the twilight zone.
+	MaxZones, MaxTwilightPoints int
+
+	// The maximum storage area locations, function and instruction
+	// definitions, stack depth and instruction size of the hinting program.
+	MaxStorage, MaxFunctionDefs, MaxInstructionDefs int
+	MaxStackElements, MaxSizeOfInstructions         int
+
+	// The maximum number of components and nesting depth of composite
+	// glyphs.
+	MaxComponentElements, MaxComponentDepth int
+}
+
+// MaxpTable returns the maximum profile ('maxp') table of the font. If the
+// font is not an SFNT font then ErrTableMissing is returned.
+func (f *Font) MaxpTable() (*MaxpTable, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_maxp)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_MaxProfile)(p)
+	return &MaxpTable{
+		Version:               versionToFloat(t.version),
+		NumGlyphs:             int(t.numGlyphs),
+		MaxPoints:             int(t.maxPoints),
+		MaxContours:           int(t.maxContours),
+		MaxCompositePoints:    int(t.maxCompositePoints),
+		MaxCompositeContours:  int(t.maxCompositeContours),
+		MaxZones:              int(t.maxZones),
+		MaxTwilightPoints:     int(t.maxTwilightPoints),
+		MaxStorage:            int(t.maxStorage),
+		MaxFunctionDefs:       int(t.maxFunctionDefs),
+		MaxInstructionDefs:    int(t.maxInstructionDefs),
+		MaxStackElements:      int(t.maxStackElements),
+		MaxSizeOfInstructions: int(t.maxSizeOfInstructions),
+		MaxComponentElements:  int(t.maxComponentElements),
+		MaxComponentDepth:     int(t.maxComponentDepth),
+	}, nil
+}
+
+// PCLTTable is the PCL 5 ('PCLT') table of an SFNT font. Unless noted
+// otherwise, values are expressed in font units.
+type PCLTTable struct {
+	// The version of the table.
+	Version float64
+
+	// The PCL font number of the font.
+	FontNumber uint32
+
+	// The width of the space glyph.
+	Pitch int
+
+	// The height of lowercase and uppercase letters.
+	XHeight, CapHeight int
+
+	// The PCL style word, type family and symbol set of the font.
+	Style, TypeFamily, SymbolSet uint16
+
+	// The name of the typeface, the character complement bit set of the font,
+	// and its suggested file name.
+	Typeface            string
+	CharacterComplement [8]byte
+	FileName            string
+
+	// The stroke weight (e.g. 0 for medium, 3 for bold) and width type (e.g. 0
+	// for normal, -2 for condensed) of the font.
+	StrokeWeight, WidthType int
+
+	// The PCL serif style of the font.
+	SerifStyle byte
+}
+
+// PCLTTable returns the PCL 5 ('PCLT') table of the font. If the font is not
+// an SFNT font, or has no such table, then ErrTableMissing is returned.
+func (f *Font) PCLTTable() (*PCLTTable, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	p := f.getSfntTable(C.ft_sfnt_pclt)
+	if p == nil {
+		return nil, ErrTableMissing
+	}
+	t := (*C.TT_PCLT)(p)
+	if t.Version == 0 {
+		return nil, ErrTableMissing
+	}
+	pclt := &PCLTTable{
+		Version:      versionToFloat(t.Version),
+		FontNumber:   uint32(t.FontNumber),
+		Pitch:        int(t.Pitch),
+		XHeight:      int(t.xHeight),
+		CapHeight:    int(t.CapHeight),
+		Style:        uint16(t.Style),
+		TypeFamily:   uint16(t.TypeFamily),
+		SymbolSet:    uint16(t.SymbolSet),
+		Typeface:     tableString(t.TypeFace[:]),
+		FileName:     tableString(t.FileName[:]),
+		StrokeWeight: int(t.StrokeWeight),
+		WidthType:    int(t.WidthType),
+		SerifStyle:   byte(t.SerifStyle),
+	}
+	for i, c := range t.CharacterComplement {
+		pclt.CharacterComplement[i] = byte(c)
+	}
+	return pclt, nil
+}
+
+// getSfntTable returns a pointer to the given table of the font, or nil if the
+// font has no such table. The font lock must be held.
+func (f *Font) getSfntTable(tag C.FT_Sfnt_Tag) unsafe.Pointer {
+	if f.c.face_flags&C.FT_FACE_FLAG_SFNT == 0 {
+		return nil
+	}
+	return C.FT_Get_Sfnt_Table(f.c, tag)
+}
+
+// fixedToFloat converts a 16.16 fixed point value to a float.
+func fixedToFloat(v C.FT_Fixed) float64 {
+	return float64(v) / 65536
+}
+
+// versionToFloat converts an SFNT Version16Dot16 value, whose minor version is
+// a single digit held in the upper nibble of its low 16 bits (e.g. 0x00005000
+// for version 0.5), to a float.
+func versionToFloat(v C.FT_Fixed) float64 {
+	u := uint32(v)
+	return float64(u>>16) + float64(u>>12&0xf)/10
+}
+
+// longDateTime converts an SFNT date, split into its high and low 32 bits, to
+// a time.
+func longDateTime(hi, lo uint32) time.Time {
+	// Seconds between the SFNT epoch (1904) and the Unix one.
+	const epoch = 2082844800
+	secs := int64(hi)<<32 | int64(lo)
+	return time.Unix(secs-epoch, 0).UTC()
+}
+
+// tableString converts a fixed size, space or NUL padded, string field of an
+// SFNT table to a string.
+func tableString(s []C.FT_Char) string {
+	b := make([]byte, 0, len(s))
+	for _, c := range s {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	for len(b) > 0 && b[len(b)-1] == ' ' {
+		b = b[:len(b)-1]
+	}
+	return string(b)
+}
@@ -0,0 +1,184 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+)
+
+// testBDF is a minimal BDF font with a single glyph, used to test non-SFNT
+// faces.
+const testBDF = `STARTFONT 2.1
+FONT -test-test-medium-r-normal--8-80-75-75-c-80-iso10646-1
+SIZE 8 75 75
+FONTBOUNDINGBOX 8 8 0 0
+STARTPROPERTIES 2
+FONT_ASCENT 8
+FONT_DESCENT 0
+ENDPROPERTIES
+CHARS 1
+STARTCHAR A
+ENCODING 65
+SWIDTH 1000 0
+DWIDTH 8 0
+BBX 8 8 0 0
+BITMAP
+18
+24
+42
+42
+7E
+42
+42
+00
+ENDCHAR
+ENDFONT
+`
+
+func TestSfntTables(t *testing.T) {
+	font := loadTestFont(t, "VeraIt.ttf")
+
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.MagicNumber != 0x5F0F3CF5 {
+		t.Errorf("head: MagicNumber %#x", head.MagicNumber)
+	}
+	if head.UnitsPerEm != font.UnitsPerEm || head.BBox != font.BBox {
+		t.Errorf("head: UnitsPerEm %d, BBox %v", head.UnitsPerEm, head.BBox)
+	}
+	if head.FontRevision != 2 || head.Created.Year() != 2003 {
+		t.Errorf("head: FontRevision %v, Created %v", head.FontRevision, head.Created)
+	}
+
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hhea.Ascender != font.Ascender || hhea.AdvanceMax != font.MaxAdvanceWidth {
+		t.Errorf("hhea: Ascender %d, AdvanceMax %d", hhea.Ascender, hhea.AdvanceMax)
+	}
+
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os2.WeightClass != 400 || os2.WidthClass != 5 || os2.VendorID != "Bits" {
+		t.Errorf("OS/2: WeightClass %d, WidthClass %d, VendorID %q", os2.WeightClass, os2.WidthClass, os2.VendorID)
+	}
+	if os2.Selection&SelectionItalic == 0 {
+		t.Errorf("OS/2: Selection %#x has no italic flag", os2.Selection)
+	}
+	if os2.TypoAscender != 1556 || os2.TypoDescender != -492 || os2.WinAscent != 1901 || os2.WinDescent != 483 {
+		t.Errorf("OS/2: typo %d/%d, win %d/%d", os2.TypoAscender, os2.TypoDescender, os2.WinAscent, os2.WinDescent)
+	}
+
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.ItalicAngle != -11 || post.UnderlinePosition != -213 || post.IsFixedPitch {
+		t.Errorf("post: ItalicAngle %v, UnderlinePosition %d, IsFixedPitch %v", post.ItalicAngle, post.UnderlinePosition, post.IsFixedPitch)
+	}
+
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxp.NumGlyphs != font.NumGlyphs {
+		t.Errorf("maxp: NumGlyphs %d, want %d", maxp.NumGlyphs, font.NumGlyphs)
+	}
+
+	pclt, err := font.PCLTTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pclt.Typeface != "VeraSansOb" {
+		t.Errorf("PCLT: Typeface %q", pclt.Typeface)
+	}
+
+	// Vera has no vertical metrics.
+	if _, err := font.VheaTable(); err != ErrTableMissing {
+		t.Errorf("vhea: got error %v, want %v", err, ErrTableMissing)
+	}
+}
+
+func TestSfntTableVersions(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Version16Dot16 values hold the minor version in a nibble, so 0x00005000 is
+	// version 0.5 (as used by CFF fonts) rather than 0.3125.
+	be := binary.BigEndian
+	be.PutUint32(sfntTable(t, data, "maxp"), 0x00005000)
+	be.PutUint32(sfntTable(t, data, "hhea"), 0x00011000)
+	be.PutUint32(sfntTable(t, data, "post"), 0x00025000)
+
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxp.Version != 0.5 || maxp.NumGlyphs != font.NumGlyphs {
+		t.Errorf("maxp: Version %v, NumGlyphs %d", maxp.Version, maxp.NumGlyphs)
+	}
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hhea.Version != 1.1 {
+		t.Errorf("hhea: Version %v, want 1.1", hhea.Version)
+	}
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Format != 2.5 {
+		t.Errorf("post: Format %v, want 2.5", post.Format)
+	}
+}
+
+func TestSfntTablesMissing(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if font.IsSFNT() {
+		t.Fatal("BDF font is an SFNT font")
+	}
+
+	tables := map[string]func() error{
+		"head": func() error { _, err := font.HeadTable(); return err },
+		"hhea": func() error { _, err := font.HheaTable(); return err },
+		"vhea": func() error { _, err := font.VheaTable(); return err },
+		"OS/2": func() error { _, err := font.OS2Table(); return err },
+		"post": func() error { _, err := font.PostTable(); return err },
+		"maxp": func() error { _, err := font.MaxpTable(); return err },
+		"PCLT": func() error { _, err := font.PCLTTable(); return err },
+	}
+	for name, get := range tables {
+		if err := get(); err != ErrTableMissing {
+			t.Errorf("%s: got error %v, want %v", name, err, ErrTableMissing)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_SIZES_H
+*/
+import "C"
+
+import "runtime"
+
+// SizeMetrics contains the metrics of a font at a specific size. Unlike the
+// metrics of a Font, which are expressed in font units, they are scaled to the
+// size and, for hinted fonts, rounded to whole pixels.
+type SizeMetrics struct {
+	// The horizontal and vertical size in pixels per EM (ppem).
+	XPPEM, YPPEM int
+
+	// The scales converting horizontal and vertical font units to 26.6 pixel
+	// units at this size.
+	XScale, YScale float64
+
+	// The ascender, descender (negative below the baseline) and the vertical
+	// distance between two consecutive baselines.
+	// Expressed in 26.6 pixel units.
+	Ascender, Descender, Height int
+
+	// The maximum advance width of all glyphs.
+	// Expressed in 26.6 pixel units.
+	MaxAdvance int
+}
+
+// Size is an additional size of a font, which shares the font's face but
+// loads glyphs at its own size. Several sizes of a single font can thus be
+// used alternately without resizing the font back and forth:
+//
+//	small, err := font.NewSize(12*64, 12*64, 72, 72)
+//	large, err := font.NewSize(48*64, 48*64, 72, 72)
+//	a, err := small.Load(font.Index('a'))
+//	A, err := large.Load(font.Index('A'))
+//
+// Glyphs loaded from a size remain at that size, even when reloaded into the
+// font's glyph slot after other glyphs were loaded.
+type Size struct {
+	// Holds *Font to avoid GC.
+	font *Font
+	c    C.FT_Size
+}
+
+// NewSize returns a new size of the font given 26.6 width and height units and
+// X/Y axis resolutions, see SetSize.
+func (f *Font) NewSize(width, height, xResolution, yResolution int) (*Size, error) {
+	s, err := f.newSize()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.SetSize(width, height, xResolution, yResolution); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewSizePixels returns a new size of the font given width and height pixel
+// based units, see SetSizePixels.
+func (f *Font) NewSizePixels(width, height int) (*Size, error) {
+	s, err := f.newSize()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.SetSizePixels(width, height); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newSize creates a new size of the font, with no size set.
+func (f *Font) newSize() (*Size, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	s := &Size{font: f}
+	err := C.FT_New_Size(f.c, &s.c)
+	if err != 0 {
+		return nil, f.newError("FT_New_Size", int(err))
+	}
+
+	runtime.SetFinalizer(s, func(s *Size) {
+		s.font.lock()
+		defer s.font.unlock()
+
+		// Closing the font or context releases its sizes.
+		if s.font.closed || s.font.ctx.closed {
+			return
+		}
+
+		// Make sure the face is not left with a dangling active size.
+		if s.font.c.size == s.c {
+			C.FT_Activate_Size(s.font.size)
+		}
+		C.FT_Done_Size(s.c)
+	})
+	return s, nil
+}
+
+// Font returns the font this is a size of.
+func (s *Size) Font() *Font {
+	return s.font
+}
+
+// SetSize sets the size given 26.6 width and height units and X/Y axis
+// resolutions, and returns the metrics of the font at that size.
+func (s *Size) SetSize(width, height, xResolution, yResolution int) (SizeMetrics, error) {
+	f := s.font
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return SizeMetrics{}, err
+	}
+
+	if err := f.activate(s); err != nil {
+		return SizeMetrics{}, err
+	}
+	return f.setSize(width, height, xResolution, yResolution)
+}
+
+// SetSizePixels sets the size given width and height pixel based units, and
+// returns the metrics of the font at that size.
+func (s *Size) SetSizePixels(width, height int) (SizeMetrics, error) {
+	f := s.font
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return SizeMetrics{}, err
+	}
+
+	if err := f.activate(s); err != nil {
+		return SizeMetrics{}, err
+	}
+	return f.setSizePixels(width, height)
+}
+
+// Metrics returns the metrics of the font at this size.
+func (s *Size) Metrics() SizeMetrics {
+	f := s.font
+	f.lock()
+	defer f.unlock()
+
+	if f.checkOpen() != nil {
+		return SizeMetrics{}
+	}
+
+	return newSizeMetrics(&s.c.metrics)
+}
+
+// Kerning is like Font.Kerning, except the kerning is scaled to this size.
+func (s *Size) Kerning(leftGlyph, rightGlyph rune) (x, y int, e error) {
+	f := s.font
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return 0, 0, err
+	}
+
+	if err := f.activate(s); err != nil {
+		return 0, 0, err
+	}
+	return f.kerning(leftGlyph, rightGlyph)
+}
+
+// Load loads the given glyph index at this size into the font's glyph slot and
+// returns the glyph. It is short-hand for:
+//
+//	s.LoadWithOptions(glyphIndex, nil)
+func (s *Size) Load(glyphIndex uint) (*Glyph, error) {
+	return s.LoadWithOptions(glyphIndex, nil)
+}
+
+// LoadWithOptions is like Font.LoadWithOptions, except the glyph is loaded at
+// this size.
+func (s *Size) LoadWithOptions(glyphIndex uint, opts *LoadOptions) (*Glyph, error) {
+	f := s.font
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	return f.load(s, glyphIndex, opts)
+}
+
+// sizeMetrics returns the metrics of the active size, the font lock must be
+// held.
+func (f *Font) sizeMetrics() SizeMetrics {
+	return newSizeMetrics(&f.c.size.metrics)
+}
+
+// newSizeMetrics returns a copy of the given FreeType size metrics.
+func newSizeMetrics(m *C.FT_Size_Metrics) SizeMetrics {
+	return SizeMetrics{
+		XPPEM:      int(m.x_ppem),
+		YPPEM:      int(m.y_ppem),
+		XScale:     fixedToFloat(m.x_scale),
+		YScale:     fixedToFloat(m.y_scale),
+		Ascender:   int(m.ascender),
+		Descender:  int(m.descender),
+		Height:     int(m.height),
+		MaxAdvance: int(m.max_advance),
+	}
+}
+
+// activate makes the given size, or the font's own size if it is nil, the
+// active size of the face. The font lock must be held.
+func (f *Font) activate(s *Size) error {
+	size := f.size
+	if s != nil {
+		size = s.c
+	}
+	if f.c.size == size {
+		return nil
+	}
+	err := C.FT_Activate_Size(size)
+	if err != 0 {
+		return f.newError("FT_Activate_Size", int(err))
+	}
+	return nil
+}
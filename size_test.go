@@ -0,0 +1,140 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	index := font.Index('A')
+
+	want, err := font.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	small, err := font.NewSizePixels(12, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := font.NewSize(48*64, 48*64, 72, 72)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.Font() != font {
+		t.Error("Size.Font returned another font")
+	}
+
+	a, err := small.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A, err := large.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if A.Height < 3*a.Height {
+		t.Errorf("48px height %d, 12px height %d", A.Height, a.Height)
+	}
+
+	// The glyphs are reloaded at their own size into the glyph slot.
+	for _, g := range []*Glyph{a, A, a} {
+		img, err := g.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h := img.Bounds().Dy(); h != (g.Height+63)/64 {
+			t.Errorf("image height %d, want %d", h, (g.Height+63)/64)
+		}
+	}
+
+	// The font's own size is unaffected.
+	got, err := font.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Height != want.Height || got.HMetrics != want.HMetrics {
+		t.Errorf("font size changed: got %+v, want %+v", got.HMetrics, want.HMetrics)
+	}
+
+	if _, err := small.SetSizePixels(24, 24); err != nil {
+		t.Fatal(err)
+	}
+	b, err := small.Load(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Height <= a.Height || b.Height >= A.Height {
+		t.Errorf("24px height %d, want between %d and %d", b.Height, a.Height, A.Height)
+	}
+}
+
+func TestSizeFinalizer(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	for i := 0; i < 10; i++ {
+		s, err := font.NewSizePixels(10+i, 10+i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Load(font.Index('A')); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runtime.GC()
+	runtime.GC()
+	if _, err := font.Load(font.Index('A')); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSizeMetrics(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	m, err := font.SetSizePixels(0, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.XPPEM != 32 || m.YPPEM != 32 {
+		t.Errorf("ppem %dx%d, want 32x32", m.XPPEM, m.YPPEM)
+	}
+	if want := 32 * 64 / float64(font.UnitsPerEm); m.YScale < want-0.01 || m.YScale > want+0.01 {
+		t.Errorf("YScale %v, want %v", m.YScale, want)
+	}
+	// Hinted metrics are rounded to whole pixels.
+	for _, v := range []int{m.Ascender, m.Descender, m.Height, m.MaxAdvance} {
+		if v%64 != 0 {
+			t.Errorf("metric %d is not rounded to whole pixels", v)
+		}
+	}
+	if m.Ascender <= 0 || m.Descender >= 0 || m.Height <= 0 {
+		t.Errorf("got %+v", m)
+	}
+	if got := font.SizeMetrics(); got != m {
+		t.Errorf("SizeMetrics() = %+v, want %+v", got, m)
+	}
+
+	m2, err := font.SetSize(16*64, 16*64, 144, 144)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2 != m {
+		t.Errorf("16pt at 144dpi: got %+v, want %+v", m2, m)
+	}
+
+	// Additional sizes have their own metrics.
+	s, err := font.NewSizePixels(0, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Metrics(); got.YPPEM != 64 || got.Ascender < 2*m.Ascender-64 {
+		t.Errorf("64px: got %+v", got)
+	}
+	if got := font.SizeMetrics(); got != m {
+		t.Errorf("font SizeMetrics() = %+v, want %+v", got, m)
+	}
+}
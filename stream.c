@@ -0,0 +1,35 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include <stdint.h>
+#include <stdlib.h>
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include "_cgo_export.h"
+
+// The I/O functions of streams created by newStream, which forward to the Go
+// functions in stream.go.
+static unsigned long streamRead(FT_Stream stream, unsigned long offset, unsigned char* buffer, unsigned long count) {
+	return goStreamRead(stream, offset, buffer, count);
+}
+
+static void streamClose(FT_Stream stream) {
+	goStreamClose(stream);
+	free(stream);
+}
+
+// newStream allocates a stream of the given size whose descriptor pointer holds
+// the given handle, or returns NULL if out of memory. FreeType closes the
+// stream, which frees it, when the face using it is released.
+FT_Stream newStream(unsigned long size, uintptr_t handle) {
+	FT_Stream stream = calloc(1, sizeof(FT_StreamRec));
+	if (stream == NULL) {
+		return NULL;
+	}
+	stream->size = size;
+	stream->descriptor.pointer = (void*)handle;
+	stream->read = streamRead;
+	stream->close = streamClose;
+	return stream;
+}
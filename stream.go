@@ -0,0 +1,237 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include <ft2build.h>
+#include FT_FREETYPE_H
+
+// Defined in stream.c.
+FT_Stream newStream(unsigned long size, uintptr_t handle);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/cgo"
+	"sync/atomic"
+	"unsafe"
+)
+
+// LoadReaderAt loads the first face of the font file of the given size read
+// from r, and returns the loaded font or an error. Unlike Load, which needs the
+// whole font file in memory, only the parts of it which FreeType needs are read
+// from r, as they are needed.
+//
+// r must remain usable while the font, or a clone of it, is in use. It is only
+// read from with the font lock held (see Context), thus never concurrently for
+// a single font.
+//
+// If reading from r fails then the *Error returned by the font operation which
+// read from it wraps the error of r, and matches ErrInvalidStreamRead.
+func (c *Context) LoadReaderAt(r io.ReaderAt, size int64) (*Font, error) {
+	return c.LoadReaderAtFace(r, size, 0)
+}
+
+// LoadReaderAtFace is like LoadReaderAt, except it loads the face with the
+// given index from the font file, like LoadFace.
+func (c *Context) LoadReaderAtFace(r io.ReaderAt, size int64, faceIndex int) (*Font, error) {
+	if size < 0 {
+		return nil, ErrInvalidArgument
+	}
+	return c.loadStream(&streamSource{r: r, size: size}, faceIndex)
+}
+
+// LoadFile is like LoadReaderAt, except the font file is read from the file at
+// the given path. The file is kept open until the font and its clones are
+// closed or garbage collected.
+func (c *Context) LoadFile(path string) (*Font, error) {
+	return c.LoadFileFace(path, 0)
+}
+
+// LoadFileFace is like LoadFile, except it loads the face with the given index
+// from the font file, like LoadFace.
+func (c *Context) LoadFileFace(path string, faceIndex int) (*Font, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Hold a reference while loading, so that the file is closed if loading
+	// fails.
+	src := &streamSource{r: file, size: fi.Size(), file: file}
+	src.acquire()
+	defer src.release()
+	return c.loadStream(src, faceIndex)
+}
+
+// loadStream loads the face with the given index from the font file of the
+// source, see LoadReaderAt.
+func (c *Context) loadStream(src *streamSource, faceIndex int) (*Font, error) {
+	if faceIndex < 0 {
+		return nil, ErrInvalidArgument
+	}
+
+	c.access.Lock()
+	if c.closed {
+		c.access.Unlock()
+		return nil, ErrClosed
+	}
+
+	s, err := newStream(src)
+	if err != nil {
+		c.access.Unlock()
+		return nil, err
+	}
+	args := C.FT_Open_Args{
+		flags:  C.FT_OPEN_STREAM,
+		stream: s.c,
+	}
+	var face C.FT_Face
+	ftErr := C.FT_Open_Face(c.c, &args, C.FT_Long(faceIndex), &face)
+	if ftErr != 0 {
+		// FreeType may or may not have closed the stream already.
+		if !s.closed {
+			s.release()
+			C.free(unsafe.Pointer(s.c))
+		}
+		c.access.Unlock()
+		return nil, s.wrap(newError("FT_Open_Face", int(ftErr)))
+	}
+	c.access.Unlock()
+
+	return c.newFont(&Font{ctx: c, c: face, stream: s}), nil
+}
+
+// streamSource is the font file of fonts loaded using LoadReaderAt or LoadFile,
+// which is shared by their clones.
+type streamSource struct {
+	r    io.ReaderAt
+	size int64
+
+	// The file to close once no stream uses the source, or nil.
+	file *os.File
+	refs int32
+}
+
+// acquire adds a reference to the source.
+func (src *streamSource) acquire() {
+	atomic.AddInt32(&src.refs, 1)
+}
+
+// release removes a reference from the source, closing its file once there is
+// none left.
+func (src *streamSource) release() {
+	if atomic.AddInt32(&src.refs, -1) == 0 && src.file != nil {
+		src.file.Close()
+	}
+}
+
+// stream is a FreeType stream reading from a source, used by a single face.
+// Its fields are guarded by the lock of the face's font.
+type stream struct {
+	src    *streamSource
+	c      C.FT_Stream
+	handle cgo.Handle
+
+	// The first error of the reader since the font lock was acquired, or nil.
+	err error
+
+	// Whether the stream has been closed.
+	closed bool
+}
+
+// newStream creates a new stream reading from the source.
+func newStream(src *streamSource) (*stream, error) {
+	s := &stream{src: src}
+	s.handle = cgo.NewHandle(s)
+	s.c = C.newStream(C.ulong(src.size), C.uintptr_t(s.handle))
+	if s.c == nil {
+		s.handle.Delete()
+		return nil, ErrOutOfMemory
+	}
+	src.acquire()
+	return s, nil
+}
+
+// release releases the handle of the stream and its reference to the source,
+// the C stream itself is freed separately.
+func (s *stream) release() {
+	s.closed = true
+	s.handle.Delete()
+	s.src.release()
+}
+
+// wrap returns the given error, changed to wrap the error of the reader if
+// reading from the stream failed. The stream may be nil, for fonts loaded from
+// memory.
+func (s *stream) wrap(e *Error) *Error {
+	if s != nil && s.err != nil {
+		e.Code = streamReadCode
+		e.Err = s.err
+		s.err = nil
+	}
+	return e
+}
+
+// streamReadCode is the FreeType error code of ErrInvalidStreamRead.
+const streamReadCode = 0x54
+
+// newError is like the newError function, except the error wraps the error of
+// the reader if reading the font file failed (see LoadReaderAt).
+func (f *Font) newError(op string, code int) *Error {
+	return f.stream.wrap(newError(op, code))
+}
+
+// newGlyphError is like newError, except the error is for the given glyph
+// index.
+func (f *Font) newGlyphError(op string, code int, glyphIndex uint) *Error {
+	return f.stream.wrap(newGlyphError(op, code, glyphIndex))
+}
+
+// streamOf returns the Go stream of the given FreeType stream, whose descriptor
+// pointer holds its handle. Unlike the descriptor value, a long, it is wide
+// enough for handles on all platforms.
+func streamOf(c C.FT_Stream) *stream {
+	h := *(*C.uintptr_t)(unsafe.Pointer(&c.descriptor))
+	return cgo.Handle(h).Value().(*stream)
+}
+
+//export goStreamRead
+func goStreamRead(c C.FT_Stream, offset C.ulong, buffer *C.uchar, count C.ulong) C.ulong {
+	s := streamOf(c)
+	if count == 0 {
+		// A seek, which fails past the end of the stream.
+		if int64(offset) > s.src.size {
+			return 1
+		}
+		return 0
+	}
+
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(count))
+	n, err := s.src.r.ReadAt(buf, int64(offset))
+	if n < len(buf) && s.err == nil {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = fmt.Errorf("read %d of %d bytes at offset %d: %w", n, len(buf), offset, io.ErrUnexpectedEOF)
+		}
+		s.err = err
+	}
+	return C.ulong(n)
+}
+
+//export goStreamClose
+func goStreamClose(c C.FT_Stream) {
+	streamOf(c).release()
+}
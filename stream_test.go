@@ -0,0 +1,176 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// countingReader is a reader which counts the bytes read from it, and fails
+// reads overlapping the range [from, to) with the given error.
+type countingReader struct {
+	r        *bytes.Reader
+	n        int64
+	from, to int64
+	err      error
+}
+
+var errBrokenReader = errors.New("broken reader")
+
+func (r *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < r.to && off+int64(len(p)) > r.from {
+		return 0, r.err
+	}
+	n, err := r.r.ReadAt(p, off)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestLoadReaderAt(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	mem, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &countingReader{r: bytes.NewReader(data)}
+	font, err := ctx.LoadReaderAt(r, int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if font.FamilyName != mem.FamilyName || font.NumGlyphs != mem.NumGlyphs {
+		t.Errorf("got %q with %d glyphs, want %q with %d", font.FamilyName, font.NumGlyphs, mem.FamilyName, mem.NumGlyphs)
+	}
+
+	want, err := renderRunes(mem, "Stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := renderRunes(font, "Stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("glyph %d differs from that of the font loaded from memory", i)
+		}
+	}
+	if r.n >= int64(len(data)) {
+		t.Errorf("read %d bytes, want less than the %d bytes of the font file", r.n, len(data))
+	}
+
+	clone, err := font.Clone(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := renderRunes(clone, "Clone"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadReaderAtError(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	// Fail all reads, opening the face fails.
+	r := &countingReader{r: bytes.NewReader(data), to: int64(len(data)), err: errBrokenReader}
+	if _, err := ctx.LoadReaderAt(r, int64(len(data))); !errors.Is(err, ErrInvalidStreamRead) || !errors.Is(err, errBrokenReader) {
+		t.Errorf("LoadReaderAt: got error %v, want ErrInvalidStreamRead wrapping errBrokenReader", err)
+	}
+
+	// Fail reads of the glyph outlines, loading glyphs fails.
+	glyf := sfntTable(t, data, "glyf")
+	offset := int64(bytes.Index(data, glyf))
+	r = &countingReader{
+		r:    bytes.NewReader(data),
+		from: offset,
+		to:   offset + int64(len(glyf)),
+		err:  errBrokenReader,
+	}
+	font, err := ctx.LoadReaderAt(r, int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = font.Load(font.Index('A'))
+	var ftErr *Error
+	if !errors.As(err, &ftErr) || ftErr.Op != "FT_Load_Glyph" {
+		t.Fatalf("Load: got error %v, want *Error for FT_Load_Glyph", err)
+	}
+	if !errors.Is(err, ErrInvalidStreamRead) || !errors.Is(err, errBrokenReader) {
+		t.Errorf("Load: got error %v, want ErrInvalidStreamRead wrapping errBrokenReader", err)
+	}
+
+	// Read errors are not reported by later operations.
+	if _, err := font.Load(uint(font.NumGlyphs + 10)); err == nil || errors.Is(err, ErrInvalidStreamRead) {
+		t.Errorf("Load of invalid glyph after failed read: got error %v", err)
+	}
+
+	// Reads which end early, as if the font file was truncated.
+	r.err = io.EOF
+	if _, err := font.Load(font.Index('B')); !errors.Is(err, ErrInvalidStreamRead) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Load from truncated reader: got error %v, want ErrInvalidStreamRead wrapping io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	if _, err := ctx.LoadFile("vera/missing.ttf"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v, want fs.ErrNotExist", err)
+	}
+
+	font, err := ctx.LoadFile("vera/VeraBd.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if font.StyleName != "Bold" {
+		t.Errorf("got style %q, want \"Bold\"", font.StyleName)
+	}
+	clone, err := font.Clone(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is closed once the font and its clone are.
+	file := font.stream.src.file
+	font.Close()
+	if _, err := renderRunes(clone, "Bold"); err != nil {
+		t.Fatal(err)
+	}
+	clone.Close()
+	if _, err := file.ReadAt(make([]byte, 4), 0); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("ReadAt after closing the fonts: got error %v, want os.ErrClosed", err)
+	}
+
+	// As is that of a file which fails to load.
+	if _, err := ctx.LoadFile("stream_test.go"); !errors.Is(err, ErrUnknownFileFormat) {
+		t.Errorf("got error %v, want ErrUnknownFileFormat", err)
+	}
+}
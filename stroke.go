@@ -0,0 +1,229 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_GLYPH_H
+#include FT_STROKER_H
+*/
+import "C"
+
+import (
+	"image"
+	"image/draw"
+	"unsafe"
+)
+
+// LineCap is the shape of the ends of open contours of a stroke, they map onto
+// FreeType's FT_STROKER_LINECAP_* caps. Glyph contours are closed, so this only
+// matters for unusual fonts.
+type LineCap int
+
+const (
+	// LineCapButt ends the stroke exactly at the end point.
+	LineCapButt LineCap = C.FT_STROKER_LINECAP_BUTT
+
+	// LineCapRound ends the stroke with a half circle around the end point.
+	LineCapRound LineCap = C.FT_STROKER_LINECAP_ROUND
+
+	// LineCapSquare ends the stroke with a square extending past the end point
+	// by the radius.
+	LineCapSquare LineCap = C.FT_STROKER_LINECAP_SQUARE
+)
+
+// LineJoin is the shape of the corners of a stroke, they map onto FreeType's
+// FT_STROKER_LINEJOIN_* joins.
+type LineJoin int
+
+const (
+	// LineJoinRound rounds corners with a circular arc.
+	LineJoinRound LineJoin = C.FT_STROKER_LINEJOIN_ROUND
+
+	// LineJoinBevel cuts corners off with a straight line.
+	LineJoinBevel LineJoin = C.FT_STROKER_LINEJOIN_BEVEL
+
+	// LineJoinMiter extends corners to a point, unless the point is further
+	// than the miter limit from the corner, in which case the miter is cut off
+	// at the limit.
+	LineJoinMiter LineJoin = C.FT_STROKER_LINEJOIN_MITER_VARIABLE
+
+	// LineJoinMiterFixed is like LineJoinMiter, except corners past the miter
+	// limit are beveled instead.
+	LineJoinMiterFixed LineJoin = C.FT_STROKER_LINEJOIN_MITER_FIXED
+)
+
+// StrokeBorder selects which side of the glyph outline is stroked.
+type StrokeBorder int
+
+const (
+	// StrokeBoth strokes both sides of the outline, centering the stroke on
+	// it.
+	StrokeBoth StrokeBorder = iota
+
+	// StrokeOutside strokes only the outside of the outline, i.e. the stroke
+	// surrounds the filled glyph without covering it.
+	StrokeOutside
+
+	// StrokeInside strokes only the inside of the outline, i.e. the stroke lies
+	// within the filled glyph.
+	StrokeInside
+)
+
+// StrokeOptions describes how a glyph is stroked, see Glyph.Stroke.
+type StrokeOptions struct {
+	// The radius of the stroke, i.e. half its width for StrokeBoth and its
+	// full width otherwise.
+	// Expressed in 26.6 pixel units.
+	Radius int
+
+	// The shape of the ends and corners of the stroke.
+	Cap  LineCap
+	Join LineJoin
+
+	// The miter limit of LineJoinMiter and LineJoinMiterFixed joins, as a
+	// multiple of the radius (e.g. 4). Values below 1 are treated as 1.
+	MiterLimit float64
+
+	// The side of the outline which is stroked.
+	Border StrokeBorder
+}
+
+// Stroke renders the stroke of the glyph's outline using RenderNormal, and
+// returns the resulting alpha image. Like that returned by Image, its bounds
+// are relative to the glyph origin, so the stroke and the glyph are composited
+// by drawing both images at the same point:
+//
+//	opts := StrokeOptions{Radius: 2 * 64, Join: LineJoinRound, Border: StrokeOutside}
+//	stroke, err := g.Stroke(opts)
+//	fill, err := g.Image()
+//	draw.DrawMask(dst, stroke.Bounds().Add(p), black, image.Point{}, stroke, stroke.Bounds().Min, draw.Over)
+//	draw.DrawMask(dst, fill.Bounds().Add(p), white, image.Point{}, fill, fill.Bounds().Min, draw.Over)
+//
+// Only outline glyphs can be stroked, for others (e.g. embedded bitmaps)
+// ErrInvalidGlyphFormat is returned.
+func (g *Glyph) Stroke(opts StrokeOptions) (*GlyphImage, error) {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	var stroker C.FT_Stroker
+	ftErr := C.FT_Stroker_New(g.font.ctx.c, &stroker)
+	if ftErr != 0 {
+		return nil, newError("FT_Stroker_New", int(ftErr))
+	}
+	defer C.FT_Stroker_Done(stroker)
+	miterLimit := opts.MiterLimit
+	if !(miterLimit >= 1) {
+		miterLimit = 1
+	}
+	C.FT_Stroker_Set(
+		stroker,
+		C.FT_Fixed(opts.Radius),
+		C.FT_Stroker_LineCap(opts.Cap),
+		C.FT_Stroker_LineJoin(opts.Join),
+		floatToFixed(miterLimit),
+	)
+
+	// Stroke a copy of the glyph, which FreeType replaces by the stroke and
+	// then by its bitmap.
+	tmp, err := g.glyphCopy()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		C.FT_Done_Glyph(tmp)
+	}()
+	if tmp.format != C.FT_GLYPH_FORMAT_OUTLINE {
+		return nil, ErrInvalidGlyphFormat
+	}
+
+	// A single border is the outline of the glyph grown (or shrunk) by the
+	// radius, the stroke is the difference between it and the glyph.
+	var fill *image.Alpha
+	if opts.Border == StrokeOutside || opts.Border == StrokeInside {
+		var cpy C.FT_Glyph
+		ftErr = C.FT_Glyph_Copy(tmp, &cpy)
+		if ftErr != 0 {
+			return nil, newGlyphError("FT_Glyph_Copy", int(ftErr), g.index)
+		}
+		fill, err = glyphAlpha(&cpy, g.index)
+		C.FT_Done_Glyph(cpy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	op := "FT_Glyph_StrokeBorder"
+	switch opts.Border {
+	case StrokeBoth:
+		op = "FT_Glyph_Stroke"
+		ftErr = C.FT_Glyph_Stroke(&tmp, stroker, 1)
+	case StrokeOutside:
+		ftErr = C.FT_Glyph_StrokeBorder(&tmp, stroker, 0, 1)
+	case StrokeInside:
+		ftErr = C.FT_Glyph_StrokeBorder(&tmp, stroker, 1, 1)
+	default:
+		return nil, ErrInvalidArgument
+	}
+	if ftErr != 0 {
+		return nil, newGlyphError(op, int(ftErr), g.index)
+	}
+	border, err := glyphAlpha(&tmp, g.index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Border {
+	case StrokeOutside:
+		subtractAlpha(border, fill)
+	case StrokeInside:
+		subtractAlpha(fill, border)
+		border = fill
+	}
+	return &GlyphImage{
+		glyph: g,
+		Alpha: border,
+	}, nil
+}
+
+// glyphAlpha renders the given outline glyph using RenderNormal, replacing it
+// by its bitmap, and returns a copy of the bitmap as an alpha image.
+func glyphAlpha(glyph *C.FT_Glyph, index uint) (*image.Alpha, error) {
+	ftErr := C.FT_Glyph_To_Bitmap(glyph, C.FT_RENDER_MODE_NORMAL, nil, 1)
+	if ftErr != 0 {
+		return nil, newGlyphError("FT_Glyph_To_Bitmap", int(ftErr), index)
+	}
+	bg := C.FT_BitmapGlyph(unsafe.Pointer(*glyph))
+	img, err := newBitmap(&bg.bitmap, bg.left, bg.top).image()
+	if err != nil {
+		return nil, err
+	}
+	alpha, ok := img.(*image.Alpha)
+	if !ok {
+		alpha = image.NewAlpha(img.Bounds())
+		draw.Draw(alpha, alpha.Rect, img, img.Bounds().Min, draw.Src)
+	}
+	return alpha, nil
+}
+
+// subtractAlpha subtracts the coverage of b from that of a.
+func subtractAlpha(a, b *image.Alpha) {
+	r := a.Rect.Intersect(b.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i, j := a.PixOffset(x, y), b.PixOffset(x, y)
+			if a.Pix[i] > b.Pix[j] {
+				a.Pix[i] -= b.Pix[j]
+			} else {
+				a.Pix[i] = 0
+			}
+		}
+	}
+}
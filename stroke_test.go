@@ -0,0 +1,151 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestStroke(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 48); err != nil {
+		t.Fatal(err)
+	}
+	g, err := font.Load(font.Index('I'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill, err := g.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stroke := func(border StrokeBorder, radius int) *GlyphImage {
+		img, err := g.Stroke(StrokeOptions{
+			Radius:     radius * 64,
+			Join:       LineJoinMiter,
+			MiterLimit: 4,
+			Border:     border,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+	both := stroke(StrokeBoth, 2)
+	outside := stroke(StrokeOutside, 2)
+	inside := stroke(StrokeInside, 2)
+
+	// Strokes on the outside extend the glyph by the radius, those on the
+	// inside stay within it.
+	want := fill.Bounds().Inset(-2)
+	if got := both.Bounds(); !near(got, want) {
+		t.Errorf("StrokeBoth bounds %v, want %v", got, want)
+	}
+	if got := outside.Bounds(); !near(got, want) {
+		t.Errorf("StrokeOutside bounds %v, want %v", got, want)
+	}
+	if got := inside.Bounds(); !near(got, fill.Bounds()) {
+		t.Errorf("StrokeInside bounds %v, want %v", got, fill.Bounds())
+	}
+
+	// The middle of the stem of the 'I', which is about 5 pixels wide, is only
+	// covered by the fill and thin strokes.
+	b := fill.Bounds()
+	mid := image.Pt((b.Min.X+b.Max.X)/2, (b.Min.Y+b.Max.Y)/2)
+	if a := fill.AlphaAt(mid.X, mid.Y).A; a != 0xff {
+		t.Errorf("fill alpha %#x at %v, want 0xff", a, mid)
+	}
+	if a := outside.AlphaAt(mid.X, mid.Y).A; a != 0 {
+		t.Errorf("StrokeOutside alpha %#x at %v, want 0", a, mid)
+	}
+	if a := stroke(StrokeInside, 1).AlphaAt(mid.X, mid.Y).A; a != 0 {
+		t.Errorf("StrokeInside alpha %#x at %v, want 0", a, mid)
+	}
+
+	// Just outside the stem is only covered by outside strokes.
+	out := image.Pt(b.Min.X-1, mid.Y)
+	if a := outside.AlphaAt(out.X, out.Y).A; a != 0xff {
+		t.Errorf("StrokeOutside alpha %#x at %v, want 0xff", a, out)
+	}
+	if a := inside.AlphaAt(out.X, out.Y).A; a != 0 {
+		t.Errorf("StrokeInside alpha %#x at %v, want 0", a, out)
+	}
+
+	// Persistent glyphs can be stroked too.
+	cpy, err := g.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.Load(font.Index('O')); err != nil {
+		t.Fatal(err)
+	}
+	img, err := cpy.Stroke(StrokeOptions{Radius: 2 * 64, Join: LineJoinMiter, MiterLimit: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != both.Bounds() {
+		t.Errorf("persistent glyph stroke bounds %v, want %v", img.Bounds(), both.Bounds())
+	}
+
+	// A miter limit below 1, including the zero value, is treated as 1.
+	v, err := font.Load(font.Index('V'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	miter := func(limit float64) *GlyphImage {
+		img, err := v.Stroke(StrokeOptions{Radius: 2 * 64, Join: LineJoinMiter, MiterLimit: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+	one := miter(1)
+	for _, limit := range []float64{0, -1} {
+		img := miter(limit)
+		if img.Bounds() != one.Bounds() || !bytes.Equal(img.Pix, one.Pix) {
+			t.Errorf("MiterLimit %v: stroke differs from MiterLimit 1", limit)
+		}
+	}
+
+	if _, err := g.Stroke(StrokeOptions{Border: StrokeBorder(-1)}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("invalid border: got error %v, want ErrInvalidArgument", err)
+	}
+}
+
+// near reports whether each edge of the rectangles is within a pixel of the
+// other.
+func near(a, b image.Rectangle) bool {
+	abs := func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+	return abs(a.Min.X-b.Min.X) <= 1 && abs(a.Min.Y-b.Min.Y) <= 1 &&
+		abs(a.Max.X-b.Max.X) <= 1 && abs(a.Max.Y-b.Max.Y) <= 1
+}
+
+func TestStrokeBitmap(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := font.Load(font.Index('A'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Stroke(StrokeOptions{Radius: 64}); !errors.Is(err, ErrInvalidGlyphFormat) {
+		t.Errorf("got error %v, want ErrInvalidGlyphFormat", err)
+	}
+}
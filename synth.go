@@ -0,0 +1,255 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_GLYPH_H
+#include FT_OUTLINE_H
+#include FT_BITMAP_H
+*/
+import "C"
+
+import "unsafe"
+
+// Oblique is the slant used by FreeType's FT_GlyphSlot_Oblique, about 12
+// degrees, which is a good choice for LoadOptions.Oblique and Glyph.Oblique.
+const Oblique = float64(0x0366A) / 0x10000
+
+// EmboldenStrength returns the strength used by FreeType's
+// FT_GlyphSlot_Embolden at the font's current size, 1/24 of the EM size, which
+// is a good choice for LoadOptions.EmboldenX and EmboldenY and for
+// Glyph.Embolden.
+// Expressed in 26.6 pixel units.
+func (f *Font) EmboldenStrength() int {
+	f.lock()
+	defer f.unlock()
+
+	if f.checkOpen() != nil {
+		return 0
+	}
+
+	m := f.c.size.metrics
+	return int(C.FT_MulFix(C.FT_Long(f.c.units_per_EM), m.y_scale) / 24)
+}
+
+// Embolden makes the glyph bolder, widening its strokes by x horizontally and
+// y vertically (in 26.6 pixel units), like LoadOptions.EmboldenX and
+// EmboldenY. Its metrics are adjusted to match.
+//
+// Embedded bitmaps are emboldened by whole pixels, rounding a non-zero x down
+// to at least one pixel and y down. The glyph is emboldened by the rounded sum
+// of the strengths of all calls, as if it was loaded with them.
+func (g *Glyph) Embolden(x, y int) error {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return err
+	}
+
+	if x == 0 && y == 0 {
+		return nil
+	}
+	if g.c == nil {
+		oldX, oldY := g.opts.EmboldenX, g.opts.EmboldenY
+		g.opts.EmboldenX += x
+		g.opts.EmboldenY += y
+		return g.resynthesize(oldX, oldY)
+	}
+	outline, bitmap, top := g.image()
+	x, y, err := g.embolden(outline, bitmap, x, y)
+	if err != nil {
+		return err
+	}
+	if top != nil {
+		*top += C.FT_Int(y >> 6)
+	}
+	g.adjustMetrics(x, y, outline)
+	return nil
+}
+
+// Oblique slants the glyph, shifting each point of its outline horizontally
+// by slant times its height above the baseline, like LoadOptions.Oblique. Its
+// metrics are adjusted to match.
+//
+// Embedded bitmaps cannot be slanted and are left unchanged.
+func (g *Glyph) Oblique(slant float64) error {
+	g.font.lock()
+	defer g.font.unlock()
+
+	if err := g.font.checkOpen(); err != nil {
+		return err
+	}
+
+	if slant == 0 {
+		return nil
+	}
+	if g.c == nil {
+		g.opts.Oblique += slant
+		return g.resynthesize(g.opts.EmboldenX, g.opts.EmboldenY)
+	}
+	outline, _, _ := g.image()
+	if outline != nil {
+		shear(outline, slant)
+		g.adjustMetrics(0, 0, outline)
+	}
+	return nil
+}
+
+// resynthesize reloads the glyph into the font's glyph slot after its
+// synthesis options were changed, and adjusts its metrics for the change of
+// its emboldening strengths from oldX and oldY. The font lock must be held.
+func (g *Glyph) resynthesize(oldX, oldY int) error {
+	g.font.slot = nil
+	slot, err := g.slot()
+	if err != nil {
+		return err
+	}
+	x, y := g.opts.EmboldenX, g.opts.EmboldenY
+	switch slot.format {
+	case C.FT_GLYPH_FORMAT_OUTLINE:
+		g.adjustMetrics(x-oldX, y-oldY, &slot.outline)
+	case C.FT_GLYPH_FORMAT_BITMAP:
+		// The bitmap is emboldened by the rounded total strengths, which
+		// differ from the sum of the rounded changes.
+		x, y = bitmapStrength(x, y)
+		oldX, oldY = bitmapStrength(oldX, oldY)
+		g.adjustMetrics(x-oldX, y-oldY, nil)
+	}
+	return nil
+}
+
+// synthesize applies the synthetic emboldening and slanting of the glyph's
+// options to its image in the font's glyph slot, and also to its metrics if
+// adjust is true. The font lock must be held.
+func (g *Glyph) synthesize(slot C.FT_GlyphSlot, adjust bool) error {
+	o := &g.opts
+	if o.EmboldenX == 0 && o.EmboldenY == 0 && o.Oblique == 0 {
+		return nil
+	}
+
+	var (
+		outline *C.FT_Outline
+		bitmap  *C.FT_Bitmap
+	)
+	switch slot.format {
+	case C.FT_GLYPH_FORMAT_OUTLINE:
+		outline = &slot.outline
+	case C.FT_GLYPH_FORMAT_BITMAP:
+		// The bitmap may belong to the font driver.
+		err := C.FT_GlyphSlot_Own_Bitmap(slot)
+		if err != 0 {
+			return g.font.newGlyphError("FT_GlyphSlot_Own_Bitmap", int(err), g.index)
+		}
+		bitmap = &slot.bitmap
+	default:
+		return nil
+	}
+
+	x, y, err := g.embolden(outline, bitmap, o.EmboldenX, o.EmboldenY)
+	if err != nil {
+		return err
+	}
+	if bitmap != nil {
+		slot.bitmap_top += C.FT_Int(y >> 6)
+	}
+	if outline != nil && o.Oblique != 0 {
+		shear(outline, o.Oblique)
+	}
+	if adjust {
+		g.adjustMetrics(x, y, outline)
+	}
+	return nil
+}
+
+// image returns the outline, or the bitmap and its top offset, of a persistent
+// glyph. The others are nil, as are all of them for other glyph formats.
+func (g *Glyph) image() (*C.FT_Outline, *C.FT_Bitmap, *C.FT_Int) {
+	switch g.c.format {
+	case C.FT_GLYPH_FORMAT_OUTLINE:
+		og := C.FT_OutlineGlyph(unsafe.Pointer(g.c))
+		return &og.outline, nil, nil
+	case C.FT_GLYPH_FORMAT_BITMAP:
+		bg := C.FT_BitmapGlyph(unsafe.Pointer(g.c))
+		return nil, &bg.bitmap, &bg.top
+	}
+	return nil, nil, nil
+}
+
+// embolden emboldens the given outline or bitmap, one of which is nil, by x and
+// y, and returns the strengths actually used. The font lock must be held.
+func (g *Glyph) embolden(outline *C.FT_Outline, bitmap *C.FT_Bitmap, x, y int) (int, int, error) {
+	if x == 0 && y == 0 {
+		return 0, 0, nil
+	}
+	switch {
+	case outline != nil:
+		err := C.FT_Outline_EmboldenXY(outline, C.FT_Pos(x), C.FT_Pos(y))
+		if err != 0 {
+			return 0, 0, g.font.newGlyphError("FT_Outline_EmboldenXY", int(err), g.index)
+		}
+	case bitmap != nil:
+		x, y = bitmapStrength(x, y)
+		err := C.FT_Bitmap_Embolden(g.font.ctx.c, bitmap, C.FT_Pos(x), C.FT_Pos(y))
+		if err != 0 {
+			return 0, 0, g.font.newGlyphError("FT_Bitmap_Embolden", int(err), g.index)
+		}
+	default:
+		return 0, 0, nil
+	}
+	return x, y, nil
+}
+
+// bitmapStrength rounds emboldening strengths to whole pixels for bitmaps, like
+// FT_GlyphSlot_Embolden, except that a zero x strength is left as is.
+func bitmapStrength(x, y int) (int, int) {
+	if x != 0 {
+		x &^= 63
+		if x == 0 {
+			x = 64
+		}
+	}
+	return x, y &^ 63
+}
+
+// shear slants the outline by the given slant.
+func shear(outline *C.FT_Outline, slant float64) {
+	m := C.FT_Matrix{
+		xx: 0x10000,
+		xy: floatToFixed(slant),
+		yx: 0,
+		yy: 0x10000,
+	}
+	C.FT_Outline_Transform(outline, &m)
+}
+
+// adjustMetrics adjusts the metrics of the glyph after its outline or bitmap
+// was emboldened by x and y, like FT_GlyphSlot_Embolden. The bounds of outline
+// glyphs are taken from the control box of the given outline, grid-fitted
+// unless the glyph is unhinted.
+func (g *Glyph) adjustMetrics(x, y int, outline *C.FT_Outline) {
+	g.HMetrics.Advance += x
+	g.VMetrics.Advance += y
+	if outline == nil {
+		g.Width += x
+		g.Height += y
+		g.HMetrics.BearingY += y
+		return
+	}
+
+	var cbox C.FT_BBox
+	C.FT_Outline_Get_CBox(outline, &cbox)
+	xMin, yMin, xMax, yMax := int(cbox.xMin), int(cbox.yMin), int(cbox.xMax), int(cbox.yMax)
+	if g.opts.Flags&(LoadNoHinting|LoadNoScale) == 0 {
+		xMin, yMin = xMin&^63, yMin&^63
+		xMax, yMax = (xMax+63)&^63, (yMax+63)&^63
+	}
+	g.Width = xMax - xMin
+	g.Height = yMax - yMin
+	g.HMetrics.BearingX = xMin
+	g.HMetrics.BearingY = yMax
+}
@@ -0,0 +1,276 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"image"
+	"testing"
+)
+
+// metricsBounds returns the pixel bounds of the glyph's image described by its
+// metrics, relative to the glyph origin like GlyphImage.Bounds.
+func metricsBounds(g *Glyph) image.Rectangle {
+	x, y := g.HMetrics.BearingX, -g.HMetrics.BearingY
+	return image.Rect(x>>6, y>>6, (x+g.Width)>>6, (y+g.Height)>>6)
+}
+
+func TestEmbolden(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 48); err != nil {
+		t.Fatal(err)
+	}
+	strength := font.EmboldenStrength()
+	if strength != 2*64 {
+		t.Errorf("EmboldenStrength %d, want %d", strength, 2*64)
+	}
+
+	load := func(opts LoadOptions) (*Glyph, *GlyphImage) {
+		g, err := font.LoadWithOptions(font.Index('I'), &opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := g.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !near(img.Bounds(), metricsBounds(g)) {
+			t.Errorf("image bounds %v, metrics bounds %v", img.Bounds(), metricsBounds(g))
+		}
+		return g, img
+	}
+	regular, regularImg := load(DefaultLoadOptions)
+	opts := DefaultLoadOptions
+	opts.EmboldenX, opts.EmboldenY = strength, strength
+	bold, boldImg := load(opts)
+
+	if got, want := bold.HMetrics.Advance, regular.HMetrics.Advance+strength; got != want {
+		t.Errorf("emboldened advance %d, want %d", got, want)
+	}
+	if got, want := boldImg.Bounds().Dx(), regularImg.Bounds().Dx()+2; got < want-1 || got > want+1 {
+		t.Errorf("emboldened image width %d, want about %d", got, want)
+	}
+	if got, want := boldImg.Bounds().Dy(), regularImg.Bounds().Dy()+2; got < want-1 || got > want+1 {
+		t.Errorf("emboldened image height %d, want about %d", got, want)
+	}
+
+	// Emboldening a loaded glyph is the same as loading it emboldened.
+	g, _ := load(DefaultLoadOptions)
+	if err := g.Embolden(strength, strength); err != nil {
+		t.Fatal(err)
+	}
+	if g.HMetrics != bold.HMetrics || g.Width != bold.Width || g.Height != bold.Height {
+		t.Errorf("Embolden metrics %+v %dx%d, want %+v %dx%d", g.HMetrics, g.Width, g.Height, bold.HMetrics, bold.Width, bold.Height)
+	}
+	img, err := g.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != boldImg.Bounds() {
+		t.Errorf("Embolden image bounds %v, want %v", img.Bounds(), boldImg.Bounds())
+	}
+
+	// Including persistent glyphs.
+	regular, _ = load(DefaultLoadOptions)
+	cpy, err := regular.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cpy.Embolden(strength, strength); err != nil {
+		t.Fatal(err)
+	}
+	if cpy.HMetrics != bold.HMetrics || cpy.Width != bold.Width || cpy.Height != bold.Height {
+		t.Errorf("persistent Embolden metrics %+v %dx%d, want %+v %dx%d", cpy.HMetrics, cpy.Width, cpy.Height, bold.HMetrics, bold.Width, bold.Height)
+	}
+	img, err = cpy.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != boldImg.Bounds() {
+		t.Errorf("persistent Embolden image bounds %v, want %v", img.Bounds(), boldImg.Bounds())
+	}
+}
+
+func TestOblique(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 48); err != nil {
+		t.Fatal(err)
+	}
+
+	regular, err := font.Load(font.Index('I'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	regularImg, err := regular.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultLoadOptions
+	opts.Oblique = Oblique
+	g, err := font.LoadWithOptions(font.Index('I'), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := g.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(img.Bounds(), metricsBounds(g)) {
+		t.Errorf("image bounds %v, metrics bounds %v", img.Bounds(), metricsBounds(g))
+	}
+
+	if g.HMetrics.Advance != regular.HMetrics.Advance {
+		t.Errorf("slanted advance %d, want %d", g.HMetrics.Advance, regular.HMetrics.Advance)
+	}
+	if img.Bounds().Dx() <= regularImg.Bounds().Dx() {
+		t.Errorf("slanted image width %d, want more than %d", img.Bounds().Dx(), regularImg.Bounds().Dx())
+	}
+
+	// The glyph leans right: the top of the stem is right of its bottom.
+	b := img.Bounds()
+	leftmost := func(y int) int {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.AlphaAt(x, y).A == 0xff {
+				return x
+			}
+		}
+		return b.Max.X
+	}
+	if top, bottom := leftmost(b.Min.Y+1), leftmost(b.Max.Y-2); top <= bottom {
+		t.Errorf("stem starts at x=%d at the top and x=%d at the bottom, want it to lean right", top, bottom)
+	}
+
+	// Slanting a loaded glyph is the same as loading it slanted.
+	if err := regular.Oblique(Oblique); err != nil {
+		t.Fatal(err)
+	}
+	if regular.HMetrics != g.HMetrics || regular.Width != g.Width {
+		t.Errorf("Oblique metrics %+v %d wide, want %+v %d wide", regular.HMetrics, regular.Width, g.HMetrics, g.Width)
+	}
+}
+
+func TestEmboldenBitmap(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := font.Load(font.Index('A'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	regularImg, err := regular.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bitmaps are emboldened by at least a pixel, and not slanted.
+	opts := DefaultLoadOptions
+	opts.EmboldenX = 32
+	opts.Oblique = Oblique
+	g, err := font.LoadWithOptions(font.Index('A'), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.HMetrics.Advance, regular.HMetrics.Advance+64; got != want {
+		t.Errorf("emboldened advance %d, want %d", got, want)
+	}
+	img, err := g.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), metricsBounds(g); got != want {
+		t.Errorf("image bounds %v, metrics bounds %v", got, want)
+	}
+	if got, want := img.Bounds().Dx(), regularImg.Bounds().Dx()+1; got != want {
+		t.Errorf("emboldened image width %d, want %d", got, want)
+	}
+
+	// The bitmap of the font is left unchanged.
+	regular, err = font.Load(font.Index('A'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = regular.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != regularImg.Bounds() {
+		t.Errorf("image bounds %v after emboldening, want %v", img.Bounds(), regularImg.Bounds())
+	}
+}
+
+func TestSynthesizeBitmapMetrics(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	load := func() *Glyph {
+		g, err := font.Load(font.Index('A'))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+	check := func(name string, g *Glyph, wantAdvance, wantWidth int) {
+		img, err := g.Image()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := img.Bounds(), metricsBounds(g); got != want {
+			t.Errorf("%s: image bounds %v, metrics bounds %v", name, got, want)
+		}
+		if g.HMetrics.Advance != wantAdvance || img.Bounds().Dx() != wantWidth {
+			t.Errorf("%s: advance %d and image width %d, want %d and %d", name, g.HMetrics.Advance, img.Bounds().Dx(), wantAdvance, wantWidth)
+		}
+	}
+	regular := load()
+	advance := regular.HMetrics.Advance
+	img, err := regular.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	width := img.Bounds().Dx()
+
+	// Slanting leaves bitmaps and their metrics unchanged.
+	g := load()
+	if err := g.Oblique(Oblique); err != nil {
+		t.Fatal(err)
+	}
+	check("Oblique", g, advance, width)
+
+	// Strengths of repeated calls are summed before rounding them to pixels.
+	g = load()
+	for i := 0; i < 2; i++ {
+		if err := g.Embolden(10, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check("Embolden twice", g, advance+64, width+1)
+	if err := g.Embolden(108, 0); err != nil {
+		t.Fatal(err)
+	}
+	check("Embolden thrice", g, advance+128, width+2)
+
+	// Whereas persistent glyphs are emboldened by each call.
+	cpy, err := load().Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := cpy.Embolden(10, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check("persistent Embolden twice", cpy, advance+128, width+2)
+}
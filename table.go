@@ -0,0 +1,186 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_TRUETYPE_TABLES_H
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+)
+
+// Tag is the four character tag of an SFNT table, e.g. MakeTag("GSUB").
+type Tag uint32
+
+// MakeTag returns the tag for the given string, which is padded with spaces or
+// truncated to four characters.
+func MakeTag(s string) Tag {
+	var t Tag
+	for i := 0; i < 4; i++ {
+		c := byte(' ')
+		if i < len(s) {
+			c = s[i]
+		}
+		t = t<<8 | Tag(c)
+	}
+	return t
+}
+
+// String returns the four characters of the tag, e.g. "OS/2".
+func (t Tag) String() string {
+	return string([]byte{byte(t >> 24), byte(t >> 16), byte(t >> 8), byte(t)})
+}
+
+// ChecksumError is returned by Font.Table when the checksum of a table does not
+// match the one recorded in the table directory of the font.
+type ChecksumError struct {
+	// The tag of the table.
+	Tag Tag
+
+	// The checksum recorded in the table directory, and that of the table's
+	// data.
+	Want, Got uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch in %q table (got %#08x, want %#08x)", e.Tag.String(), e.Got, e.Want)
+}
+
+// TableTags returns the tags of the tables of the font, in the order of its
+// table directory. If the font is not an SFNT font then ErrTableMissing is
+// returned.
+func (f *Font) TableTags() ([]Tag, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	if f.c.face_flags&C.FT_FACE_FLAG_SFNT == 0 {
+		return nil, ErrTableMissing
+	}
+	var tags []Tag
+	for i := C.FT_UInt(0); ; i++ {
+		var tag, length C.FT_ULong
+		if C.FT_Sfnt_Table_Info(f.c, i, &tag, &length) != 0 {
+			break
+		}
+		tags = append(tags, Tag(tag))
+	}
+	return tags, nil
+}
+
+// Table returns the raw data of the table with the given tag. If the font is
+// not an SFNT font, or has no such table, then ErrTableMissing is returned.
+//
+// The checksum of the table is verified against the table directory of the
+// font, if they do not match then the data is returned along with a
+// *ChecksumError.
+func (f *Font) Table(tag Tag) ([]byte, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	if f.c.face_flags&C.FT_FACE_FLAG_SFNT == 0 || tag == 0 {
+		return nil, ErrTableMissing
+	}
+	var length C.FT_ULong
+	if err := C.FT_Load_Sfnt_Table(f.c, C.FT_ULong(tag), 0, nil, &length); err != 0 {
+		return nil, f.newError("FT_Load_Sfnt_Table", int(err))
+	}
+	data, err := f.loadSfnt(tag, 0, int(length))
+	if err != nil {
+		return nil, err
+	}
+
+	want, ok, err := f.directoryChecksum(tag)
+	if err != nil {
+		return nil, err
+	}
+	if got := tableChecksum(tag, data); ok && got != want {
+		return data, &ChecksumError{Tag: tag, Want: want, Got: got}
+	}
+	return data, nil
+}
+
+// directoryChecksum returns the checksum of the table with the given tag as
+// recorded in the table directory of the font, or false if it has no such
+// table. The font lock must be held.
+func (f *Font) directoryChecksum(tag Tag) (uint32, bool, error) {
+	be := binary.BigEndian
+
+	// Find the offset table of the face, which for collections is listed in
+	// the collection header.
+	header, err := f.loadSfnt(0, 0, 12)
+	if err != nil {
+		return 0, false, err
+	}
+	offset := int64(0)
+	if string(header[:4]) == "ttcf" {
+		p, err := f.loadSfnt(0, 12+4*int64(f.c.face_index&0xFFFF), 4)
+		if err != nil {
+			return 0, false, err
+		}
+		offset = int64(be.Uint32(p))
+		if header, err = f.loadSfnt(0, offset, 12); err != nil {
+			return 0, false, err
+		}
+	}
+
+	numTables := int(be.Uint16(header[4:]))
+	dir, err := f.loadSfnt(0, offset+12, 16*numTables)
+	if err != nil {
+		return 0, false, err
+	}
+	for i := 0; i < numTables; i++ {
+		rec := dir[16*i:]
+		if Tag(be.Uint32(rec)) == tag {
+			return be.Uint32(rec[4:]), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// loadSfnt loads length bytes at the given offset of the table with the given
+// tag, or of the font file if the tag is zero. The font lock must be held.
+func (f *Font) loadSfnt(tag Tag, offset int64, length int) ([]byte, error) {
+	data := make([]byte, length)
+	if length == 0 {
+		return data, nil
+	}
+	n := C.FT_ULong(length)
+	buf := (*C.FT_Byte)(unsafe.Pointer(&data[0]))
+	err := C.FT_Load_Sfnt_Table(f.c, C.FT_ULong(tag), C.FT_Long(offset), buf, &n)
+	if err != 0 {
+		return nil, f.newError("FT_Load_Sfnt_Table", int(err))
+	}
+	return data, nil
+}
+
+// tableChecksum computes the checksum of the given table data, as the sum of
+// its big-endian 32-bit words. The checksum adjustment of the 'head' table is
+// ignored.
+func tableChecksum(tag Tag, data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var w [4]byte
+		copy(w[:], data[i:])
+		if tag == MakeTag("head") && i == 8 {
+			continue
+		}
+		sum += binary.BigEndian.Uint32(w[:])
+	}
+	return sum
+}
@@ -0,0 +1,133 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	if tag := MakeTag("OS/2"); tag != 0x4F532F32 || tag.String() != "OS/2" {
+		t.Errorf("MakeTag(\"OS/2\") = %#x (%q)", uint32(tag), tag.String())
+	}
+	if tag := MakeTag("cvt"); tag.String() != "cvt " {
+		t.Errorf("MakeTag(\"cvt\") = %q, want \"cvt \"", tag.String())
+	}
+}
+
+func TestTables(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+
+	tags, err := font.TableTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tag := range tags {
+		got = append(got, tag.String())
+	}
+	want := []string{
+		"OS/2", "PCLT", "cmap", "cvt ", "fpgm", "gasp", "glyf", "hdmx", "head",
+		"hhea", "hmtx", "kern", "loca", "maxp", "name", "post", "prep",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TableTags() = %q, want %q", got, want)
+	}
+
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range tags {
+		table, err := font.Table(tag)
+		if err != nil {
+			t.Errorf("%v: %v", tag, err)
+			continue
+		}
+		if !bytes.Equal(table, sfntTable(t, data, tag.String())) {
+			t.Errorf("%v: table data differs from the font file", tag)
+		}
+	}
+
+	if _, err := font.Table(MakeTag("GSUB")); !errors.Is(err, ErrTableMissing) {
+		t.Errorf("GSUB: got error %v, want %v", err, ErrTableMissing)
+	}
+}
+
+func TestTableChecksum(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfntTable(t, data, "name")[10]++
+
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := font.Table(MakeTag("name"))
+	cerr, ok := err.(*ChecksumError)
+	if !ok {
+		t.Fatalf("got error %v, want a *ChecksumError", err)
+	}
+	if cerr.Tag != MakeTag("name") || cerr.Got != cerr.Want+1<<8 {
+		t.Errorf("got %+v", cerr)
+	}
+	if table == nil {
+		t.Error("no table data returned with checksum error")
+	}
+
+	// Other tables are unaffected.
+	if _, err := font.Table(MakeTag("head")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectionTables(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttc := makeCollection(t, "Vera.ttf", "VeraBd.ttf")
+	for i := 0; i < 2; i++ {
+		font, err := ctx.LoadFace(ttc, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os2, err := font.Table(MakeTag("OS/2"))
+		if err != nil {
+			t.Fatalf("face %d: %v", i, err)
+		}
+		// The usWeightClass field.
+		if weight := int(os2[4])<<8 | int(os2[5]); weight != []int{400, 700}[i] {
+			t.Errorf("face %d: weight class %d", i, weight)
+		}
+	}
+}
+
+func TestTablesMissing(t *testing.T) {
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := ctx.Load([]byte(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.TableTags(); err != ErrTableMissing {
+		t.Errorf("TableTags: got error %v, want %v", err, ErrTableMissing)
+	}
+	if _, err := font.Table(MakeTag("head")); err != ErrTableMissing {
+		t.Errorf("Table: got error %v, want %v", err, ErrTableMissing)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_OUTLINE_H
+*/
+import "C"
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// DrawString draws the given text onto dst using src as the fill, and returns
+// the pen position following the text.
+//
+// The origin is the pen position on the baseline of the first line, with
+// positive Y values extending downward, as is the returned pen position.
+// Outline glyphs are drawn at their exact, subpixel, pen position, whereas
+// embedded bitmaps are drawn at the nearest whole pixel.
+//
+// Kerning is applied between glyph pairs, runes missing from the font are
+// drawn using its .notdef glyph and newlines start a new line, one line height
+// (at the current size) below the previous one.
+func (f *Font) DrawString(dst draw.Image, origin fixed.Point26_6, src image.Image, text string) (fixed.Point26_6, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return origin, err
+	}
+
+	return f.layout(origin, text, func(g *Glyph, pen fixed.Point26_6) error {
+		// Shift outlines by the fraction of a pixel the pen is past the whole
+		// pixel it is drawn at.
+		dot := image.Pt(pen.X.Round(), pen.Y.Round())
+		slot := f.c.glyph
+		if slot.format == C.FT_GLYPH_FORMAT_OUTLINE {
+			dot = image.Pt(pen.X.Floor(), pen.Y.Floor())
+			dx, dy := pen.X&63, pen.Y&63
+			if dx != 0 || dy != 0 {
+				C.FT_Outline_Translate(&slot.outline, C.FT_Pos(dx), -C.FT_Pos(dy))
+			}
+		}
+
+		bm, done, err := g.bitmap(RenderNormal)
+		if err != nil {
+			// The slot may hold the shifted outline.
+			f.slot = nil
+			return err
+		}
+		defer done()
+
+		mask, err := bm.image()
+		if err != nil {
+			return err
+		}
+		b := mask.Bounds()
+		dr := b.Add(dot)
+		draw.DrawMask(dst, dr, src, dr.Min, mask, b.Min, draw.Over)
+		return nil
+	})
+}
+
+// MeasureString returns the bounds of the given text, and the pen advance
+// past it, were it drawn by DrawString with the origin at zero. Positive Y
+// values extend downward.
+//
+// The bounds are the union of the glyphs' metric boxes, which for hinted
+// glyphs drawn at whole pixel positions match the bounds of their images.
+func (f *Font) MeasureString(text string) (bounds fixed.Rectangle26_6, advance fixed.Point26_6, err error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return fixed.Rectangle26_6{}, fixed.Point26_6{}, err
+	}
+
+	advance, err = f.layout(fixed.Point26_6{}, text, func(g *Glyph, pen fixed.Point26_6) error {
+		m := g.HMetrics
+		min := fixed.Point26_6{X: fixed.Int26_6(m.BearingX), Y: fixed.Int26_6(-m.BearingY)}
+		r := fixed.Rectangle26_6{
+			Min: min,
+			Max: min.Add(fixed.Point26_6{X: fixed.Int26_6(g.Width), Y: fixed.Int26_6(g.Height)}),
+		}
+		if !r.Empty() {
+			bounds = bounds.Union(r.Add(pen))
+		}
+		return nil
+	})
+	return
+}
+
+// layout loads each glyph of the text in turn and calls fn with it and the pen
+// position of its origin, while the glyph is loaded into the font's glyph slot.
+// The final pen position is returned. The font lock must be held.
+func (f *Font) layout(origin fixed.Point26_6, text string, fn func(g *Glyph, pen fixed.Point26_6) error) (fixed.Point26_6, error) {
+	if err := f.activate(nil); err != nil {
+		return origin, err
+	}
+	kerning := f.c.face_flags&C.FT_FACE_FLAG_KERNING != 0
+	pen := origin
+	prev := C.FT_UInt(0)
+	for _, r := range text {
+		if r == '\n' {
+			pen.X = origin.X
+			pen.Y += fixed.Int26_6(f.c.size.metrics.height)
+			prev = 0
+			continue
+		}
+
+		index := f.charIndex(r)
+		if kerning && prev != 0 && index != 0 {
+			var vec C.FT_Vector
+			err := C.FT_Get_Kerning(f.c, prev, index, C.FT_KERNING_DEFAULT, &vec)
+			if err != 0 {
+				e := f.newGlyphError("FT_Get_Kerning", int(err), uint(index))
+				e.Rune = r
+				return pen, e
+			}
+			pen.X += fixed.Int26_6(vec.x)
+		}
+		prev = index
+
+		g, err := f.load(nil, uint(index), nil)
+		if err != nil {
+			return pen, err
+		}
+		if err := fn(g, pen); err != nil {
+			return pen, err
+		}
+		pen.X += fixed.Int26_6(g.HMetrics.Advance)
+	}
+	return pen, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares img against the named golden PNG file in testdata. As
+// rasterization may differ slightly between FreeType versions, small
+// differences are tolerated.
+func checkGolden(t *testing.T, name string, img *image.Gray) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	golden, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("%s: bounds %v, want %v", name, img.Bounds(), golden.Bounds())
+	}
+
+	const tolerance = 24
+	bad := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			want := color.GrayModel.Convert(golden.At(x, y)).(color.Gray).Y
+			d := int(img.GrayAt(x, y).Y) - int(want)
+			if d < -tolerance || d > tolerance {
+				bad++
+			}
+		}
+	}
+	if bad > b.Dx()*b.Dy()/100 {
+		t.Errorf("%s: %d pixels differ from the golden image", name, bad)
+	}
+}
+
+func TestDrawString(t *testing.T) {
+	tests := []struct {
+		golden, font, text string
+	}{
+		{"drawstring_vera.png", "Vera.ttf", "Hello, World!\nAVAVA Ty To"},
+		{"drawstring_verase.png", "VeraSe.ttf", "Kerning: AV WA Yo\nmissing: []"},
+		{"drawstring_veramono.png", "VeraMono.ttf", "func main() {\n    return\n}"},
+	}
+	for _, tst := range tests {
+		font := loadTestFont(t, tst.font)
+		if _, err := font.SetSizePixels(0, 16); err != nil {
+			t.Fatal(err)
+		}
+
+		bounds, advance, err := font.MeasureString(tst.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dst := image.NewGray(image.Rect(0, 0, 200, 64))
+		draw.Draw(dst, dst.Rect, image.White, image.Point{}, draw.Src)
+		origin := fixed.P(4, 16)
+		pen, err := font.DrawString(dst, origin, image.Black, tst.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pen.Sub(origin) != advance {
+			t.Errorf("%s: DrawString advanced %v, MeasureString %v", tst.golden, pen.Sub(origin), advance)
+		}
+
+		// Everything that was drawn must lie within the measured bounds.
+		inked := image.Rectangle{}
+		for y := 0; y < dst.Rect.Dy(); y++ {
+			for x := 0; x < dst.Rect.Dx(); x++ {
+				if dst.GrayAt(x, y).Y != 0xff {
+					inked = inked.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		measured := image.Rect(bounds.Min.X.Floor(), bounds.Min.Y.Floor(), bounds.Max.X.Ceil(), bounds.Max.Y.Ceil())
+		drawn := inked.Sub(image.Pt(origin.X.Floor(), origin.Y.Floor()))
+		if !drawn.In(measured) {
+			t.Errorf("%s: drawn bounds %v outside of measured bounds %v", tst.golden, drawn, measured)
+		}
+
+		checkGolden(t, tst.golden, dst)
+	}
+}
+
+func TestDrawStringSubpixel(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.SetSizePixels(0, 16); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func(origin fixed.Point26_6) (*image.Gray, fixed.Point26_6) {
+		dst := image.NewGray(image.Rect(0, 0, 64, 32))
+		pen, err := font.DrawString(dst, origin, image.White, "lll")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dst, pen
+	}
+	whole, wholePen := render(fixed.P(4, 20))
+
+	// Half a pixel to the right, the pen keeps its fraction and the stems are
+	// spread over two pixel columns instead of being aligned to the grid.
+	half := fixed.Point26_6{X: fixed.I(4) + 32, Y: fixed.I(20)}
+	img, pen := render(half)
+	if got, want := pen.Sub(half), wholePen.Sub(fixed.P(4, 20)); got != want {
+		t.Errorf("advanced %v from a subpixel origin, want %v", got, want)
+	}
+	if pen.X&63 != 32 {
+		t.Errorf("pen %v lost its subpixel position", pen)
+	}
+	// The ink of the glyphs moves by half a pixel.
+	centroid := func(img *image.Gray) float64 {
+		var sum, weight float64
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				a := float64(img.GrayAt(x, y).Y)
+				sum += a * (float64(x) + 0.5)
+				weight += a
+			}
+		}
+		return sum / weight
+	}
+	if d := centroid(img) - centroid(whole); d < 0.25 || d > 0.75 {
+		t.Errorf("ink moved by %.3f pixels from a half pixel offset, want about 0.5", d)
+	}
+}
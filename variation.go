@@ -0,0 +1,230 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+/*
+#include <stdlib.h>
+#include <ft2build.h>
+#include FT_FREETYPE_H
+#include FT_MULTIPLE_MASTERS_H
+*/
+import "C"
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Axis is a design axis of a variable (or Multiple Master) font.
+type Axis struct {
+	// The tag of the axis, e.g. MakeTag("wght") for the weight axis.
+	Tag Tag
+
+	// The name of the axis (e.g. "Weight"), and the ID of the name records
+	// holding its localised names, see Font.Name.
+	Name   string
+	NameID NameID
+
+	// The minimum, default and maximum design coordinates of the axis.
+	Min, Default, Max float64
+}
+
+// NamedInstance is a predefined set of design coordinates of a variable font,
+// e.g. the "Bold" instance.
+type NamedInstance struct {
+	// The ID of the name records holding the localised names of the instance
+	// (e.g. "Bold"), see Font.Name.
+	NameID NameID
+
+	// The design coordinates of the instance, one for each axis of the font.
+	Coords []float64
+}
+
+// Axes returns the design axes of the font. If the font has no axes then
+// ErrNoAxes is returned.
+func (f *Font) Axes() ([]Axis, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	mm, err := f.mmVar()
+	if err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(mm))
+
+	axes := make([]Axis, int(mm.num_axis))
+	for i, a := range unsafe.Slice(mm.axis, len(axes)) {
+		axes[i] = Axis{
+			Tag:     Tag(a.tag),
+			Name:    C.GoString(a.name),
+			NameID:  NameID(a.strid),
+			Min:     fixedToFloat(a.minimum),
+			Default: fixedToFloat(a.def),
+			Max:     fixedToFloat(a.maximum),
+		}
+	}
+	return axes, nil
+}
+
+// NamedInstances returns the named instances of the font, which may be none.
+// If the font has no axes then ErrNoAxes is returned.
+func (f *Font) NamedInstances() ([]NamedInstance, error) {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	mm, err := f.mmVar()
+	if err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(mm))
+
+	n := int(mm.num_namedstyles)
+	if n == 0 {
+		return nil, nil
+	}
+	instances := make([]NamedInstance, n)
+	for i, s := range unsafe.Slice(mm.namedstyle, n) {
+		coords := unsafe.Slice(s.coords, int(mm.num_axis))
+		instances[i] = NamedInstance{
+			NameID: NameID(s.strid),
+			Coords: make([]float64, len(coords)),
+		}
+		for j, c := range coords {
+			instances[i].Coords[j] = fixedToFloat(c)
+		}
+	}
+	return instances, nil
+}
+
+// SetDesignCoords sets the design coordinates of the font, in the order of its
+// axes, which glyphs loaded afterwards use. Axes past the given coordinates are
+// set to their default, and coordinates outside of the range of their axis are
+// clamped to it. If the font has no axes then ErrNoAxes is returned, and if a
+// coordinate is NaN then ErrInvalidArgument is.
+func (f *Font) SetDesignCoords(coords []float64) error {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return err
+	}
+
+	return f.setCoords(coords, false)
+}
+
+// SetNormalizedCoords is like SetDesignCoords except the coordinates are
+// normalized ones, ranging from -1 (the minimum of the axis) through 0 (its
+// default) to 1 (its maximum). Coordinates outside of that range are clamped
+// to it.
+func (f *Font) SetNormalizedCoords(coords []float64) error {
+	f.lock()
+	defer f.unlock()
+
+	if err := f.checkOpen(); err != nil {
+		return err
+	}
+
+	return f.setCoords(coords, true)
+}
+
+// SetNamedInstance sets the design coordinates of the font to those of the
+// given named instance, as returned by NamedInstances.
+func (f *Font) SetNamedInstance(i int) error {
+	instances, err := f.NamedInstances()
+	if err != nil {
+		return err
+	}
+	if i < 0 || i >= len(instances) {
+		return ErrInvalidArgument
+	}
+	return f.SetDesignCoords(instances[i].Coords)
+}
+
+// setCoords implements SetDesignCoords and SetNormalizedCoords. The font lock
+// must be held.
+func (f *Font) setCoords(coords []float64, normalized bool) error {
+	mm, err := f.mmVar()
+	if err != nil {
+		return err
+	}
+	defer C.free(unsafe.Pointer(mm))
+
+	axes := unsafe.Slice(mm.axis, int(mm.num_axis))
+	if len(coords) > len(axes) {
+		return ErrInvalidArgument
+	}
+	fixed := make([]C.FT_Fixed, len(axes))
+	for i := range fixed {
+		switch {
+		case i < len(coords) && normalized:
+			fixed[i], err = axisCoord(coords[i], -1, 1)
+		case i < len(coords):
+			a := axes[i]
+			fixed[i], err = axisCoord(coords[i], fixedToFloat(a.minimum), fixedToFloat(a.maximum))
+		case !normalized:
+			fixed[i] = axes[i].def
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if normalized {
+		err := C.FT_Set_Var_Blend_Coordinates(f.c, C.FT_UInt(len(fixed)), &fixed[0])
+		if err != 0 {
+			return f.newError("FT_Set_Var_Blend_Coordinates", int(err))
+		}
+	} else {
+		err := C.FT_Set_Var_Design_Coordinates(f.c, C.FT_UInt(len(fixed)), &fixed[0])
+		if err != 0 {
+			return f.newError("FT_Set_Var_Design_Coordinates", int(err))
+		}
+	}
+
+	// The glyph in the slot was loaded using the previous coordinates.
+	f.slot = nil
+	return nil
+}
+
+// mmVar returns the variation descriptor of the font, which must be freed
+// using C.free, or ErrNoAxes if the font has none. The font lock must be
+// held.
+func (f *Font) mmVar() (*C.FT_MM_Var, error) {
+	if f.c.face_flags&C.FT_FACE_FLAG_MULTIPLE_MASTERS == 0 {
+		return nil, ErrNoAxes
+	}
+	var mm *C.FT_MM_Var
+	err := C.FT_Get_MM_Var(f.c, &mm)
+	if err != 0 {
+		return nil, f.newError("FT_Get_MM_Var", int(err))
+	}
+	if mm.num_axis == 0 {
+		C.free(unsafe.Pointer(mm))
+		return nil, ErrNoAxes
+	}
+	return mm, nil
+}
+
+// axisCoord converts the coordinate, clamped to the range [min, max] of its
+// axis, to 16.16 fixed point. ErrInvalidArgument is returned for NaN.
+func axisCoord(v, min, max float64) (C.FT_Fixed, error) {
+	if math.IsNaN(v) {
+		return 0, ErrInvalidArgument
+	}
+	return floatToFixed(math.Max(min, math.Min(v, max))), nil
+}
+
+// floatToFixed converts a float to the nearest 16.16 fixed point value.
+func floatToFixed(v float64) C.FT_Fixed {
+	return C.FT_Fixed(math.Round(v * 65536))
+}
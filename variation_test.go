@@ -0,0 +1,202 @@
+// Copyright 2014 The Azul3D Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freetype
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// addTable returns a copy of the given SFNT font with the table added to it.
+func addTable(data []byte, tag string, table []byte) []byte {
+	be := binary.BigEndian
+	numTables := int(be.Uint16(data[4:]))
+
+	// Make room for the new table record, moving all tables along.
+	out := make([]byte, 0, len(data)+16+len(table)+3)
+	out = append(out, data[:12+16*numTables]...)
+	out = append(out, make([]byte, 16)...)
+	out = append(out, data[12+16*numTables:]...)
+	be.PutUint16(out[4:], uint16(numTables+1))
+	for i := 0; i < numTables; i++ {
+		rec := out[12+16*i:]
+		be.PutUint32(rec[8:], be.Uint32(rec[8:])+16)
+	}
+	for len(out)%4 != 0 {
+		out = append(out, 0)
+	}
+
+	rec := out[12+16*numTables:]
+	copy(rec, tag)
+	be.PutUint32(rec[4:], tableChecksum(MakeTag(tag), table))
+	be.PutUint32(rec[8:], uint32(len(out)))
+	be.PutUint32(rec[12:], uint32(len(table)))
+	return append(out, table...)
+}
+
+// makeFvar builds an 'fvar' table with a weight axis from 100 to 900, and two
+// named instances at 400 and 700.
+func makeFvar() []byte {
+	be := binary.BigEndian
+	fvar := make([]byte, 16+20+2*8)
+	be.PutUint16(fvar[0:], 1)  // majorVersion
+	be.PutUint16(fvar[4:], 16) // axesArrayOffset
+	be.PutUint16(fvar[6:], 2)  // reserved
+	be.PutUint16(fvar[8:], 1)  // axisCount
+	be.PutUint16(fvar[10:], 20)
+	be.PutUint16(fvar[12:], 2) // instanceCount
+	be.PutUint16(fvar[14:], 8)
+
+	axis := fvar[16:]
+	copy(axis, "wght")
+	be.PutUint32(axis[4:], 100<<16)
+	be.PutUint32(axis[8:], 400<<16)
+	be.PutUint32(axis[12:], 900<<16)
+	be.PutUint16(axis[18:], 256)
+
+	for i, weight := range []uint32{400, 700} {
+		inst := fvar[36+8*i:]
+		be.PutUint16(inst, uint16(257+i))
+		be.PutUint32(inst[4:], weight<<16)
+	}
+	return fvar
+}
+
+// makeGvar builds a 'gvar' table for numGlyphs glyphs and one axis, which has
+// no variation data.
+func makeGvar(numGlyphs int) []byte {
+	be := binary.BigEndian
+	gvar := make([]byte, 20+2*(numGlyphs+1))
+	be.PutUint16(gvar[0:], 1) // majorVersion
+	be.PutUint16(gvar[4:], 1) // axisCount
+	be.PutUint32(gvar[8:], uint32(len(gvar)))
+	be.PutUint16(gvar[12:], uint16(numGlyphs))
+	be.PutUint32(gvar[16:], uint32(len(gvar)))
+	return gvar
+}
+
+func TestVariation(t *testing.T) {
+	data, err := ioutil.ReadFile("vera/Vera.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(sfntTable(t, data, "maxp")[4:]))
+	data = addTable(data, "fvar", makeFvar())
+	data = addTable(data, "gvar", makeGvar(numGlyphs))
+	font, err := ctx.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	axes, err := font.Axes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(axes) != 1 {
+		t.Fatalf("got %d axes, want 1", len(axes))
+	}
+	a := axes[0]
+	if a.Tag != MakeTag("wght") || a.NameID != 256 || a.Min != 100 || a.Default != 400 || a.Max != 900 {
+		t.Errorf("got axis %+v", a)
+	}
+
+	instances, err := font.NamedInstances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []NamedInstance{
+		{NameID: 257, Coords: []float64{400}},
+		{NameID: 258, Coords: []float64{700}},
+	}
+	if !reflect.DeepEqual(instances, want) {
+		t.Errorf("got instances %+v, want %+v", instances, want)
+	}
+
+	if err := font.SetDesignCoords([]float64{650.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.SetDesignCoords(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.SetNormalizedCoords([]float64{-0.25}); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.SetNamedInstance(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.SetDesignCoords([]float64{400, 100}); err != ErrInvalidArgument {
+		t.Errorf("too many coordinates: got error %v, want %v", err, ErrInvalidArgument)
+	}
+	if err := font.SetDesignCoords([]float64{1e30}); err != nil {
+		t.Errorf("out of range coordinate: %v", err)
+	}
+	if err := font.SetNormalizedCoords([]float64{-1e30}); err != nil {
+		t.Errorf("out of range normalized coordinate: %v", err)
+	}
+	if err := font.SetDesignCoords([]float64{math.NaN()}); err != ErrInvalidArgument {
+		t.Errorf("NaN coordinate: got error %v, want %v", err, ErrInvalidArgument)
+	}
+	if err := font.SetNamedInstance(2); err != ErrInvalidArgument {
+		t.Errorf("out of range instance: got error %v, want %v", err, ErrInvalidArgument)
+	}
+	if _, err := font.Load(font.Index('A')); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVariationNoAxes(t *testing.T) {
+	font := loadTestFont(t, "Vera.ttf")
+	if _, err := font.Axes(); err != ErrNoAxes {
+		t.Errorf("Axes: got error %v, want %v", err, ErrNoAxes)
+	}
+	if _, err := font.NamedInstances(); err != ErrNoAxes {
+		t.Errorf("NamedInstances: got error %v, want %v", err, ErrNoAxes)
+	}
+	if err := font.SetDesignCoords([]float64{400}); err != ErrNoAxes {
+		t.Errorf("SetDesignCoords: got error %v, want %v", err, ErrNoAxes)
+	}
+	if err := font.SetNormalizedCoords([]float64{0}); err != ErrNoAxes {
+		t.Errorf("SetNormalizedCoords: got error %v, want %v", err, ErrNoAxes)
+	}
+}
+
+func TestAxisCoord(t *testing.T) {
+	tests := []struct {
+		v, want float64
+	}{
+		{650.5, 650.5},
+		{100, 100},
+		{50, 100},
+		{1e30, 900},
+		{math.Inf(-1), 100},
+	}
+	for _, tst := range tests {
+		c, err := axisCoord(tst.v, 100, 900)
+		if err != nil || fixedToFloat(c) != tst.want {
+			t.Errorf("%v: got %v (error %v), want %v", tst.v, fixedToFloat(c), err, tst.want)
+		}
+	}
+	if _, err := axisCoord(math.NaN(), 100, 900); err != ErrInvalidArgument {
+		t.Errorf("NaN: got error %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestFixed(t *testing.T) {
+	for _, v := range []float64{0, 1, -1, 0.5, 400, -11.25, 650.5, 1.0 / 65536, 32767.99998474121} {
+		if got := fixedToFloat(floatToFixed(v)); got != v {
+			t.Errorf("%v: round-tripped to %v", v, got)
+		}
+	}
+	if got := fixedToFloat(floatToFixed(0.3)); got < 0.3-0.5/65536 || got > 0.3+0.5/65536 {
+		t.Errorf("0.3: round-tripped to %v", got)
+	}
+}